@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Work with X.509 certificates",
+	Long:  `Inspect PEM or DER encoded X.509 certificates and certificate bundles.`,
+}
+
+var certInspectCmd = &cobra.Command{
+	Use:   "inspect [pem|file|-]",
+	Short: "Show certificate details",
+	Long: `Decode PEM or DER certificates from a string, file, or stdin and print the
+subject, issuer, SANs, validity, key, and fingerprints of each one. For
+bundles with several certificates, the chain order is checked as well.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCertInspect,
+}
+
+var (
+	certFromFile     bool
+	certTimeFormat   string
+	certTimezone     string
+	certFingerprints []string
+)
+
+func init() {
+	certInspectCmd.Flags().BoolVarP(&certFromFile, "file", "f", false, "Read certificates from file instead of string")
+	certInspectCmd.Flags().StringVar(&certTimeFormat, "format", "2006-01-02 15:04:05", "Output format for validity dates")
+	certInspectCmd.Flags().StringVarP(&certTimezone, "timezone", "z", "UTC", "Timezone for validity dates (UTC, Local, or IANA name)")
+	certInspectCmd.Flags().StringSliceVar(&certFingerprints, "fingerprint", []string{"sha256", "sha1"}, "Fingerprint hash types: md5, sha1, sha256")
+	certCmd.AddCommand(certInspectCmd)
+	rootCmd.AddCommand(certCmd)
+}
+
+func runCertInspect(cmd *cobra.Command, args []string) error {
+	location, err := parseTimezone(certTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	for _, algorithm := range certFingerprints {
+		if _, err := computeHash(algorithm, nil); err != nil {
+			return fmt.Errorf("invalid --fingerprint: %w", err)
+		}
+	}
+
+	data, err := readCertInput(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	certs, err := parseCertificates(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, cert := range certs {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Certificate %d of %d\n", i+1, len(certs))
+		printCertificate(cert, location, now)
+	}
+
+	if len(certs) > 1 {
+		fmt.Println()
+		printChainOrder(certs)
+	}
+	return nil
+}
+
+// readCertInput returns the raw input from a file, the argument, or stdin
+// when no argument (or "-") is given.
+func readCertInput(cmd *cobra.Command, args []string) ([]byte, error) {
+	if len(args) == 0 || args[0] == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+	if certFromFile {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", args[0], err)
+		}
+		return data, nil
+	}
+	return []byte(args[0]), nil
+}
+
+// parseCertificates accepts one or more PEM blocks, raw DER, or DER encoded
+// as hex or base64.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		var certs []*x509.Certificate
+		rest := data
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate %d: %w", len(certs)+1, err)
+			}
+			certs = append(certs, cert)
+		}
+		if len(certs) == 0 {
+			return nil, fmt.Errorf("no CERTIFICATE blocks found in PEM input")
+		}
+		return certs, nil
+	}
+
+	der := data
+	if !bytes.HasPrefix(data, []byte{0x30}) {
+		decoded, err := readBinaryInput(string(data), "auto", false)
+		if err != nil {
+			return nil, fmt.Errorf("input is neither PEM nor DER")
+		}
+		der = decoded
+	}
+	certs, err := x509.ParseCertificates(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DER certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in DER input")
+	}
+	return certs, nil
+}
+
+// printCertificate prints the details of cert. The --fingerprint algorithms
+// are validated by runCertInspect.
+func printCertificate(cert *x509.Certificate, location *time.Location, now time.Time) {
+	fmt.Printf("  Subject:     %s\n", cert.Subject)
+	fmt.Printf("  Issuer:      %s\n", cert.Issuer)
+	fmt.Printf("  Serial:      %s\n", formatFingerprint(hex.EncodeToString(cert.SerialNumber.Bytes())))
+	if sans := certSANs(cert); len(sans) > 0 {
+		fmt.Printf("  SANs:        %s\n", strings.Join(sans, ", "))
+	}
+	fmt.Printf("  Not Before:  %s (%s)\n", formatTime(cert.NotBefore, location, certTimeFormat), certTimezone)
+	fmt.Printf("  Not After:   %s (%s)\n", formatTime(cert.NotAfter, location, certTimeFormat), certTimezone)
+	fmt.Printf("  Status:      %s\n", certStatus(cert, now))
+	fmt.Printf("  Key:         %s\n", describePublicKey(cert.PublicKey))
+	fmt.Printf("  Signature:   %s\n", cert.SignatureAlgorithm)
+	fmt.Printf("  CA:          %t\n", cert.IsCA)
+
+	for _, algorithm := range certFingerprints {
+		sum, _ := computeHash(algorithm, cert.Raw)
+		fmt.Printf("  %-12s %s\n", strings.ToUpper(algorithm)+":", formatFingerprint(sum))
+	}
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	var sans []string
+	for _, name := range cert.DNSNames {
+		sans = append(sans, "DNS:"+name)
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, "IP:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, "URI:"+uri.String())
+	}
+	return sans
+}
+
+func certStatus(cert *x509.Certificate, now time.Time) string {
+	switch {
+	case now.Before(cert.NotBefore):
+		return fmt.Sprintf("not yet valid (starts in %s)", humanDuration(cert.NotBefore.Sub(now)))
+	case now.After(cert.NotAfter):
+		return fmt.Sprintf("expired (%s ago)", humanDuration(now.Sub(cert.NotAfter)))
+	default:
+		return fmt.Sprintf("valid (expires in %s)", humanDuration(cert.NotAfter.Sub(now)))
+	}
+}
+
+// humanDuration renders a duration in days, or hours when under a day.
+func humanDuration(d time.Duration) string {
+	if days := int(d.Hours() / 24); days >= 1 {
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return fmt.Sprintf("%d hours", int(d.Hours()))
+}
+
+func describePublicKey(key interface{}) string {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d bits", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s (%d bits)", k.Curve.Params().Name, k.Curve.Params().BitSize)
+	case ed25519.PublicKey:
+		return "Ed25519 (256 bits)"
+	default:
+		return fmt.Sprintf("%T", key)
+	}
+}
+
+// formatFingerprint renders a hex digest as colon-separated uppercase pairs.
+func formatFingerprint(digest string) string {
+	digest = strings.ToUpper(digest)
+	pairs := make([]string, 0, len(digest)/2)
+	for i := 0; i+1 < len(digest); i += 2 {
+		pairs = append(pairs, digest[i:i+2])
+	}
+	return strings.Join(pairs, ":")
+}
+
+// printChainOrder reports how the certificates in a bundle chain together
+// and whether they appear leaf first, each followed by its issuer.
+func printChainOrder(certs []*x509.Certificate) {
+	issuers := make([]int, len(certs))
+	issued := make([]bool, len(certs))
+	for i, cert := range certs {
+		issuers[i] = -1
+		for j, candidate := range certs {
+			if i != j && cert.CheckSignatureFrom(candidate) == nil {
+				issuers[i] = j
+				issued[j] = true
+				break
+			}
+		}
+	}
+
+	// The leaf is the certificate that did not issue any other one.
+	leaf := 0
+	for i := range certs {
+		if !issued[i] {
+			leaf = i
+			break
+		}
+	}
+
+	var order []int
+	seen := make(map[int]bool)
+	for i := leaf; i >= 0 && !seen[i]; i = issuers[i] {
+		seen[i] = true
+		order = append(order, i)
+	}
+
+	labels := make([]string, len(order))
+	for n, i := range order {
+		role := "intermediate"
+		switch {
+		case n == 0:
+			role = "leaf"
+		case isSelfSigned(certs[i]):
+			role = "root"
+		}
+		labels[n] = fmt.Sprintf("%d (%s)", i+1, role)
+	}
+	fmt.Printf("Chain: %s\n", strings.Join(labels, " -> "))
+
+	inOrder := len(order) == len(certs)
+	for n, i := range order {
+		if i != n {
+			inOrder = false
+		}
+	}
+	if inOrder {
+		fmt.Println("✓ Bundle is in chain order")
+		return
+	}
+	if len(order) != len(certs) {
+		fmt.Printf("✗ Bundle contains %d certificate(s) outside the chain\n", len(certs)-len(order))
+		return
+	}
+	expected := make([]string, len(order))
+	for n, i := range order {
+		expected[n] = fmt.Sprint(i + 1)
+	}
+	fmt.Printf("✗ Bundle is not in chain order (expected: %s)\n", strings.Join(expected, ", "))
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer) && cert.CheckSignatureFrom(cert) == nil
+}
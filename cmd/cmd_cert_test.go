@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// testCert is a generated certificate together with its signing key.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCert(t *testing.T, cn string, isCA bool, parent *testCert) testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2099, 1, 1, 12, 0, 0, 0, time.UTC),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if !isCA {
+		template.DNSNames = []string{cn}
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return testCert{cert: cert, key: key, pem: string(block)}
+}
+
+func runCertInspectTest(t *testing.T, args []string, stdin string, flags map[string]string) (string, error) {
+	t.Helper()
+
+	// Reset flags to default values
+	certFromFile = false
+	certTimeFormat = "2006-01-02 15:04:05"
+	certTimezone = "UTC"
+	certFingerprints = []string{"sha256", "sha1"}
+
+	cmd := &cobra.Command{
+		Use:  "inspect [pem|file|-]",
+		Args: cobra.MaximumNArgs(1),
+		RunE: runCertInspect,
+	}
+	cmd.Flags().BoolVarP(&certFromFile, "file", "f", false, "Read certificates from file instead of string")
+	cmd.Flags().StringVar(&certTimeFormat, "format", "2006-01-02 15:04:05", "Output format for validity dates")
+	cmd.Flags().StringVarP(&certTimezone, "timezone", "z", "UTC", "Timezone for validity dates (UTC, Local, or IANA name)")
+	cmd.Flags().StringSliceVar(&certFingerprints, "fingerprint", []string{"sha256", "sha1"}, "Fingerprint hash types: md5, sha1, sha256")
+	cmd.SetIn(strings.NewReader(stdin))
+
+	for flag, value := range flags {
+		cmd.Flags().Set(flag, value)
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := cmd.RunE(cmd, args)
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return strings.TrimSpace(buf.String()), err
+}
+
+func TestCertInspect(t *testing.T) {
+	root := newTestCert(t, "Test Root", true, nil)
+	intermediate := newTestCert(t, "Test Intermediate", true, &root)
+	leaf := newTestCert(t, "example.com", false, &intermediate)
+
+	t.Run("single pem string", func(t *testing.T) {
+		output, err := runCertInspectTest(t, []string{leaf.pem}, "", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, want := range []string{
+			"Certificate 1 of 1",
+			"Subject:     CN=example.com",
+			"Issuer:      CN=Test Intermediate",
+			"SANs:        DNS:example.com, IP:127.0.0.1",
+			"Not Before:  2024-01-01 00:00:00 (UTC)",
+			"Not After:   2099-01-01 12:00:00 (UTC)",
+			"Status:      valid (expires in",
+			"Key:         ECDSA P-256 (256 bits)",
+			"SHA256:",
+			"SHA1:",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("timezone and format", func(t *testing.T) {
+		output, err := runCertInspectTest(t, []string{leaf.pem}, "", map[string]string{
+			"timezone": "Asia/Tokyo",
+			"format":   "2006/01/02 15:04",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(output, "Not Before:  2024/01/01 09:00 (Asia/Tokyo)") {
+			t.Errorf("Expected converted validity date, got:\n%s", output)
+		}
+	})
+
+	t.Run("bundle in order from stdin", func(t *testing.T) {
+		output, err := runCertInspectTest(t, []string{"-"}, leaf.pem+intermediate.pem+root.pem, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(output, "Chain: 1 (leaf) -> 2 (intermediate) -> 3 (root)") ||
+			!strings.Contains(output, "✓ Bundle is in chain order") {
+			t.Errorf("Expected ordered chain, got:\n%s", output)
+		}
+	})
+
+	t.Run("bundle out of order from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bundle.pem")
+		if err := os.WriteFile(path, []byte(root.pem+leaf.pem+intermediate.pem), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		output, err := runCertInspectTest(t, []string{path}, "", map[string]string{"file": "true"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(output, "✗ Bundle is not in chain order (expected: 2, 3, 1)") {
+			t.Errorf("Expected chain order warning, got:\n%s", output)
+		}
+	})
+
+	t.Run("der file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "leaf.der")
+		if err := os.WriteFile(path, leaf.cert.Raw, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		output, err := runCertInspectTest(t, []string{path}, "", map[string]string{"file": "true", "fingerprint": "md5"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(output, "MD5:") || strings.Contains(output, "SHA256:") {
+			t.Errorf("Expected only an MD5 fingerprint, got:\n%s", output)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if _, err := runCertInspectTest(t, []string{"not a certificate"}, "", nil); err == nil {
+			t.Error("Expected error for invalid input")
+		}
+	})
+
+	t.Run("invalid fingerprint before any output", func(t *testing.T) {
+		output, err := runCertInspectTest(t, []string{leaf.pem}, "", map[string]string{"fingerprint": "sha256,crc32"})
+		if err == nil {
+			t.Error("Expected error for unsupported fingerprint")
+		}
+		if output != "" {
+			t.Errorf("Expected no output, got:\n%s", output)
+		}
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		if _, err := runCertInspectTest(t, []string{leaf.pem}, "", map[string]string{"timezone": "Invalid/Zone"}); err == nil {
+			t.Error("Expected error for invalid timezone")
+		}
+	})
+}
+
+func TestFormatFingerprint(t *testing.T) {
+	if got := formatFingerprint("0a1b2c"); got != "0A:1B:2C" {
+		t.Errorf("Expected 0A:1B:2C, got %s", got)
+	}
+}
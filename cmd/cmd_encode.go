@@ -2,29 +2,42 @@ package cmd
 
 import (
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
+	"unicode"
 
 	"github.com/spf13/cobra"
 )
 
 var encodeCmd = &cobra.Command{
-	Use:   "encode [string]",
+	Use:   "encode [string|file]",
 	Short: "Encode/decode strings with various formats",
-	Long:  `Encode or decode strings using base64, URL encoding, or other formats.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runEncode,
+	Long: `Encode or decode strings using base64, URL encoding, or other formats.
+
+The protobuf type decodes raw wire-format bytes (hex, base64, or a binary
+file) into a schema-less tree of fields.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEncode,
 }
 
 var (
-	encodeType   string
-	shouldDecode bool
+	encodeType     string
+	shouldDecode   bool
+	encodeFromFile bool
+	encodeInput    string
+	encodeOutput   string
 )
 
 func init() {
-	encodeCmd.Flags().StringVarP(&encodeType, "type", "t", "base64", "Encoding type: base64, url")
+	encodeCmd.Flags().StringVarP(&encodeType, "type", "t", "base64", "Encoding type: base64, url, protobuf")
 	encodeCmd.Flags().BoolVarP(&shouldDecode, "decode", "d", false, "Decode instead of encode")
+	encodeCmd.Flags().BoolVarP(&encodeFromFile, "file", "f", false, "Read binary input from file instead of string (protobuf)")
+	encodeCmd.Flags().StringVarP(&encodeInput, "input", "i", "auto", "Input encoding for binary decoders: auto, hex, base64, raw")
+	encodeCmd.Flags().StringVarP(&encodeOutput, "output", "o", "text", "Output format for protobuf: text, json")
 	rootCmd.AddCommand(encodeCmd)
 }
 
@@ -53,8 +66,13 @@ func runEncode(cmd *cobra.Command, args []string) error {
 		} else {
 			result = url.QueryEscape(input)
 		}
+	case "protobuf":
+		if !shouldDecode {
+			return fmt.Errorf("protobuf only supports decoding (use --decode)")
+		}
+		return runDecodeProtobuf(input)
 	default:
-		return fmt.Errorf("unsupported encoding type: %s (supported: base64, url)", encodeType)
+		return fmt.Errorf("unsupported encoding type: %s (supported: base64, url, protobuf)", encodeType)
 	}
 
 	operation := "Encoded"
@@ -65,3 +83,116 @@ func runEncode(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s (%s): %s\n", operation, strings.ToUpper(encodeType), result)
 	return nil
 }
+
+func runDecodeProtobuf(input string) error {
+	data, err := readBinaryInput(input, encodeInput, encodeFromFile)
+	if err != nil {
+		return err
+	}
+
+	fields, err := decodeProtobuf(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode protobuf: %w", err)
+	}
+
+	switch strings.ToLower(encodeOutput) {
+	case "text":
+		fmt.Println("Decoded (PROTOBUF):")
+		fmt.Println(formatProtobufText(fields))
+	case "json":
+		if fields == nil {
+			fields = []protoField{}
+		}
+		output, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: text, json)", encodeOutput)
+	}
+	return nil
+}
+
+// binaryEncoders render bytes as text. They are shared by commands that
+// print binary data, such as random bytes.
+var binaryEncoders = map[string]func([]byte) string{
+	"hex":       hex.EncodeToString,
+	"base64":    base64.StdEncoding.EncodeToString,
+	"base64url": base64.RawURLEncoding.EncodeToString,
+	"raw":       func(b []byte) string { return string(b) },
+}
+
+// encodeBytes renders data in the named binary encoding.
+func encodeBytes(data []byte, format string) (string, error) {
+	encode, ok := binaryEncoders[strings.ToLower(format)]
+	if !ok {
+		return "", fmt.Errorf("unsupported encoding: %s (supported: hex, base64, base64url, raw)", format)
+	}
+	return encode(data), nil
+}
+
+// readBinaryInput returns the bytes described by input. Files are read as-is
+// unless a textual format is requested; strings are decoded as hex or base64,
+// with "auto" trying hex first.
+func readBinaryInput(input, format string, fromFile bool) ([]byte, error) {
+	format = strings.ToLower(format)
+	raw := []byte(input)
+	if fromFile {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", input, err)
+		}
+		if format == "auto" || format == "raw" {
+			return data, nil
+		}
+		raw = data
+	}
+
+	text := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(raw))
+
+	switch format {
+	case "raw":
+		return raw, nil
+	case "hex":
+		data, err := hex.DecodeString(strings.TrimPrefix(text, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode hex: %w", err)
+		}
+		return data, nil
+	case "base64":
+		return decodeAnyBase64(text)
+	case "auto":
+		if data, err := hex.DecodeString(strings.TrimPrefix(text, "0x")); err == nil {
+			return data, nil
+		}
+		data, err := decodeAnyBase64(text)
+		if err != nil {
+			return nil, fmt.Errorf("input is neither hex nor base64")
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported input encoding: %s (supported: auto, hex, base64, raw)", format)
+	}
+}
+
+// decodeAnyBase64 accepts standard and URL-safe base64, padded or not.
+func decodeAnyBase64(text string) ([]byte, error) {
+	encodings := []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding,
+		base64.URLEncoding, base64.RawURLEncoding,
+	}
+	var err error
+	for _, enc := range encodings {
+		var data []byte
+		if data, err = enc.DecodeString(text); err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to decode base64: %w", err)
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Protobuf wire types as defined by the encoding spec.
+const (
+	protoWireVarint     = 0
+	protoWireFixed64    = 1
+	protoWireBytes      = 2
+	protoWireStartGroup = 3
+	protoWireEndGroup   = 4
+	protoWireFixed32    = 5
+)
+
+// maxProtoDepth bounds the recursion when guessing nested messages.
+const maxProtoDepth = 64
+
+// protoField is a single schema-less field decoded from the wire format.
+// Only the members relevant to the wire type are populated.
+type protoField struct {
+	Number   uint64       `json:"field"`
+	WireType string       `json:"wire_type"`
+	Varint   *uint64      `json:"varint,omitempty"`
+	Sint     *int64       `json:"sint,omitempty"`
+	Int      *int64       `json:"int,omitempty"`
+	Fixed64  *uint64      `json:"fixed64,omitempty"`
+	Double   *float64     `json:"double,omitempty"`
+	Fixed32  *uint32      `json:"fixed32,omitempty"`
+	Float    *float32     `json:"float,omitempty"`
+	String   *string      `json:"string,omitempty"`
+	Bytes    string       `json:"bytes,omitempty"`
+	Message  []protoField `json:"message,omitempty"`
+	Group    []protoField `json:"group,omitempty"`
+}
+
+var errProtoTruncated = errors.New("unexpected end of input")
+
+// decodeProtobuf parses raw protobuf wire-format bytes without a schema.
+// Length-delimited fields are reported as nested messages when they parse
+// cleanly, as strings when they are printable UTF-8, and as hex otherwise.
+func decodeProtobuf(data []byte) ([]protoField, error) {
+	fields, rest, err := decodeProtoFields(data, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("unexpected end group tag at offset %d", len(data)-len(rest))
+	}
+	return fields, nil
+}
+
+// decodeProtoFields decodes fields until the input is exhausted or, when
+// inGroup is set, until the matching end-group tag. It returns the bytes
+// remaining after the end-group tag.
+func decodeProtoFields(data []byte, depth int, inGroup bool) ([]protoField, []byte, error) {
+	if depth > maxProtoDepth {
+		return nil, nil, fmt.Errorf("message nesting exceeds %d levels", maxProtoDepth)
+	}
+
+	var fields []protoField
+	offset := 0
+	for offset < len(data) {
+		tag, n := binary.Uvarint(data[offset:])
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("invalid tag at offset %d: %w", offset, errProtoTruncated)
+		}
+		offset += n
+
+		number := tag >> 3
+		wireType := tag & 0x7
+		if number == 0 || number > math.MaxInt32>>2 {
+			return nil, nil, fmt.Errorf("invalid field number %d at offset %d", number, offset-n)
+		}
+
+		field := protoField{Number: number}
+		switch wireType {
+		case protoWireVarint:
+			v, vn := binary.Uvarint(data[offset:])
+			if vn <= 0 {
+				return nil, nil, fmt.Errorf("field %d: invalid varint: %w", number, errProtoTruncated)
+			}
+			offset += vn
+			field.WireType = "varint"
+			field.Varint = &v
+			if sint := int64(v>>1) ^ -int64(v&1); sint != int64(v) {
+				field.Sint = &sint
+			}
+			if i := int64(v); i < 0 {
+				field.Int = &i
+			}
+		case protoWireFixed64:
+			if len(data)-offset < 8 {
+				return nil, nil, fmt.Errorf("field %d: fixed64: %w", number, errProtoTruncated)
+			}
+			v := binary.LittleEndian.Uint64(data[offset:])
+			offset += 8
+			d := math.Float64frombits(v)
+			field.WireType = "fixed64"
+			field.Fixed64 = &v
+			if !math.IsNaN(d) && !math.IsInf(d, 0) {
+				field.Double = &d
+			}
+		case protoWireBytes:
+			length, ln := binary.Uvarint(data[offset:])
+			if ln <= 0 {
+				return nil, nil, fmt.Errorf("field %d: invalid length: %w", number, errProtoTruncated)
+			}
+			offset += ln
+			if length > uint64(len(data)-offset) {
+				return nil, nil, fmt.Errorf("field %d: length %d: %w", number, length, errProtoTruncated)
+			}
+			payload := data[offset : offset+int(length)]
+			offset += int(length)
+			field.WireType = "len"
+			classifyProtoPayload(&field, payload, depth)
+		case protoWireStartGroup:
+			group, rest, err := decodeProtoFields(data[offset:], depth+1, true)
+			if err != nil {
+				return nil, nil, fmt.Errorf("field %d: %w", number, err)
+			}
+			offset = len(data) - len(rest)
+			field.WireType = "group"
+			field.Group = group
+		case protoWireEndGroup:
+			if !inGroup {
+				return nil, nil, fmt.Errorf("unexpected end group tag for field %d", number)
+			}
+			return fields, data[offset:], nil
+		case protoWireFixed32:
+			if len(data)-offset < 4 {
+				return nil, nil, fmt.Errorf("field %d: fixed32: %w", number, errProtoTruncated)
+			}
+			v := binary.LittleEndian.Uint32(data[offset:])
+			offset += 4
+			f := math.Float32frombits(v)
+			field.WireType = "fixed32"
+			field.Fixed32 = &v
+			if !math.IsNaN(float64(f)) && !math.IsInf(float64(f), 0) {
+				field.Float = &f
+			}
+		default:
+			return nil, nil, fmt.Errorf("invalid wire type %d for field %d", wireType, number)
+		}
+		fields = append(fields, field)
+	}
+
+	if inGroup {
+		return nil, nil, fmt.Errorf("missing end group tag: %w", errProtoTruncated)
+	}
+	return fields, nil, nil
+}
+
+// classifyProtoPayload guesses what a length-delimited payload holds.
+func classifyProtoPayload(field *protoField, payload []byte, depth int) {
+	if len(payload) == 0 || isPrintableText(payload) {
+		s := string(payload)
+		field.String = &s
+		return
+	}
+	if nested, rest, err := decodeProtoFields(payload, depth+1, false); err == nil && len(rest) == 0 {
+		field.Message = nested
+		return
+	}
+	field.Bytes = hex.EncodeToString(payload)
+}
+
+func isPrintableText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatProtobufText renders decoded fields as an indented tree, one field
+// per line, annotated with the wire type.
+func formatProtobufText(fields []protoField) string {
+	var sb strings.Builder
+	writeProtoFields(&sb, fields, 0)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func writeProtoFields(sb *strings.Builder, fields []protoField, indent int) {
+	pad := strings.Repeat("  ", indent)
+	for _, f := range fields {
+		prefix := fmt.Sprintf("%s%d (%s)", pad, f.Number, f.WireType)
+		switch {
+		case f.Message != nil:
+			sb.WriteString(prefix + " {\n")
+			writeProtoFields(sb, f.Message, indent+1)
+			sb.WriteString(pad + "}\n")
+		case f.Group != nil || f.WireType == "group":
+			sb.WriteString(prefix + " {\n")
+			writeProtoFields(sb, f.Group, indent+1)
+			sb.WriteString(pad + "}\n")
+		case f.Varint != nil:
+			value := strconv.FormatUint(*f.Varint, 10)
+			var extra []string
+			if f.Sint != nil {
+				extra = append(extra, fmt.Sprintf("sint: %d", *f.Sint))
+			}
+			if f.Int != nil {
+				extra = append(extra, fmt.Sprintf("int: %d", *f.Int))
+			}
+			sb.WriteString(prefix + ": " + value + annotateProto(extra) + "\n")
+		case f.Fixed64 != nil:
+			extra := []string{fmt.Sprintf("int: %d", int64(*f.Fixed64))}
+			if f.Double != nil {
+				extra = append(extra, fmt.Sprintf("double: %g", *f.Double))
+			}
+			sb.WriteString(fmt.Sprintf("%s: 0x%016x%s\n", prefix, *f.Fixed64, annotateProto(extra)))
+		case f.Fixed32 != nil:
+			extra := []string{fmt.Sprintf("int: %d", int32(*f.Fixed32))}
+			if f.Float != nil {
+				extra = append(extra, fmt.Sprintf("float: %g", *f.Float))
+			}
+			sb.WriteString(fmt.Sprintf("%s: 0x%08x%s\n", prefix, *f.Fixed32, annotateProto(extra)))
+		case f.String != nil:
+			sb.WriteString(prefix + ": " + strconv.Quote(*f.String) + "\n")
+		default:
+			sb.WriteString(prefix + ": 0x" + f.Bytes + "\n")
+		}
+	}
+}
+
+func annotateProto(extra []string) string {
+	if len(extra) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(extra, ", ") + ")"
+}
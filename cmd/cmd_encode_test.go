@@ -135,3 +135,141 @@ func TestEncodeValidation(t *testing.T) {
 		t.Error("Expected error when too many arguments provided")
 	}
 }
+
+func TestEncodeProtobuf(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		flags    map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "hex input text output",
+			args:     []string{"08 96 01 12 07 74 65 73 74 69 6e 67"},
+			flags:    map[string]string{"decode": "true"},
+			expected: "Decoded (PROTOBUF):\n1 (varint): 150 (sint: 75)\n2 (len): \"testing\"",
+		},
+		{
+			name:     "base64 input nested message",
+			args:     []string{"GgMIlgE="},
+			flags:    map[string]string{"decode": "true"},
+			expected: "Decoded (PROTOBUF):\n3 (len) {\n  1 (varint): 150 (sint: 75)\n}",
+		},
+		{
+			name:     "json output",
+			args:     []string{"2d0000803f"},
+			flags:    map[string]string{"decode": "true", "output": "json"},
+			expected: "[\n  {\n    \"field\": 5,\n    \"wire_type\": \"fixed32\",\n    \"fixed32\": 1065353216,\n    \"float\": 1\n  }\n]",
+		},
+		{
+			name:     "binary file input",
+			args:     []string{"testdata/test.pb"},
+			flags:    map[string]string{"decode": "true", "file": "true"},
+			expected: "Decoded (PROTOBUF):\n1 (varint): 150 (sint: 75)\n2 (len): \"testing\"",
+		},
+		{
+			name:    "encoding not supported",
+			args:    []string{"0801"},
+			flags:   map[string]string{},
+			wantErr: true,
+		},
+		{
+			name:    "truncated input",
+			args:    []string{"0a05ab"},
+			flags:   map[string]string{"decode": "true", "input": "hex"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags to default values
+			encodeType = "protobuf"
+			shouldDecode = false
+			encodeFromFile = false
+			encodeInput = "auto"
+			encodeOutput = "text"
+
+			cmd := &cobra.Command{
+				Use:  "encode [string|file]",
+				Args: cobra.ExactArgs(1),
+				RunE: runEncode,
+			}
+
+			cmd.Flags().StringVarP(&encodeType, "type", "t", "protobuf", "Encoding type: base64, url, protobuf")
+			cmd.Flags().BoolVarP(&shouldDecode, "decode", "d", false, "Decode instead of encode")
+			cmd.Flags().BoolVarP(&encodeFromFile, "file", "f", false, "Read binary input from file instead of string (protobuf)")
+			cmd.Flags().StringVarP(&encodeInput, "input", "i", "auto", "Input encoding for binary decoders: auto, hex, base64, raw")
+			cmd.Flags().StringVarP(&encodeOutput, "output", "o", "text", "Output format for protobuf: text, json")
+
+			for flag, value := range tt.flags {
+				cmd.Flags().Set(flag, value)
+			}
+
+			old := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := cmd.RunE(cmd, tt.args)
+
+			w.Close()
+			os.Stdout = old
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := strings.TrimSpace(buf.String())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if output != tt.expected {
+					t.Errorf("Expected output %q, got %q", tt.expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeProtobuf(t *testing.T) {
+	t.Run("groups", func(t *testing.T) {
+		// field 1 start group, field 2 varint 1, field 1 end group
+		fields, err := decodeProtobuf([]byte{0x0b, 0x10, 0x01, 0x0c})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(fields) != 1 || fields[0].WireType != "group" || len(fields[0].Group) != 1 {
+			t.Errorf("Expected a single group with one field, got %+v", fields)
+		}
+	})
+
+	t.Run("negative int varint", func(t *testing.T) {
+		fields, err := decodeProtobuf([]byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fields[0].Int == nil || *fields[0].Int != -1 {
+			t.Errorf("Expected int -1, got %+v", fields[0])
+		}
+	})
+
+	t.Run("opaque bytes", func(t *testing.T) {
+		fields, err := decodeProtobuf([]byte{0x0a, 0x02, 0xff, 0x00})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fields[0].Bytes != "ff00" {
+			t.Errorf("Expected bytes ff00, got %+v", fields[0])
+		}
+	})
+
+	t.Run("invalid wire type", func(t *testing.T) {
+		if _, err := decodeProtobuf([]byte{0x0e}); err == nil {
+			t.Error("Expected error for wire type 6")
+		}
+	})
+}
@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var fakeCmd = &cobra.Command{
+	Use:   "fake <kind>",
+	Short: "Generate realistic fake data for fixtures",
+	Long: `Generate realistic fake values from embedded offline corpora.
+
+Kinds: name, first-name, last-name, username, email, company, company-suffix,
+address, street, street-name, building-number, city, state, postcode, phone,
+domain, url, ipv4, ipv6, user-agent, lorem.
+
+Names, addresses, postcodes, phone numbers, and companies follow the --locale
+(en_US, en_GB, de_DE, fr_FR, es_ES), which defaults to $LC_ALL or $LANG when
+supported and en_US otherwise. Emails use the reserved example.com domains,
+domains and URLs the reserved .example and .test TLDs (RFC 2606), IP
+addresses the documentation ranges (RFC 5737, RFC 3849), and phone numbers
+fictional ranges where a locale has them, so fixtures never point at real
+people or hosts.
+
+--count, --output, --unique, and --seed work as for "plz random".`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFake,
+}
+
+var (
+	fakeLocale string
+	fakeCount  int
+	fakeOutput string
+	fakeUnique bool
+	fakeSeed   string
+)
+
+//go:embed data/fake/*.json
+var fakeData embed.FS
+
+var fakeKindNames = []string{
+	"name", "first-name", "last-name", "username", "email", "company", "company-suffix",
+	"address", "street", "street-name", "building-number", "city", "state", "postcode", "phone",
+	"domain", "url", "ipv4", "ipv6", "user-agent", "lorem",
+}
+
+// fakeCorpus holds the locale-specific data in data/fake/<locale>.json.
+// Pattern fields use # for a digit, % for a non-zero digit, and ? for an
+// uppercase letter; format fields reference other kinds as {kind}.
+type fakeCorpus struct {
+	FirstNames      []string `json:"first_names"`
+	LastNames       []string `json:"last_names"`
+	Cities          []string `json:"cities"`
+	States          []string `json:"states"`
+	StreetNames     []string `json:"street_names"`
+	BuildingNumber  []string `json:"building_number"`
+	StreetFormat    string   `json:"street_format"`
+	AddressFormat   string   `json:"address_format"`
+	Postcode        []string `json:"postcode"`
+	Phone           []string `json:"phone"`
+	CompanySuffixes []string `json:"company_suffixes"`
+	CompanyFormat   []string `json:"company_format"`
+}
+
+// fakeCommon holds the locale-independent data in data/fake/common.json.
+type fakeCommon struct {
+	EmailDomains []string `json:"email_domains"`
+	TLDs         []string `json:"tlds"`
+	DomainWords  []string `json:"domain_words"`
+	URLPaths     []string `json:"url_paths"`
+	UserAgents   []string `json:"user_agents"`
+	LoremWords   []string `json:"lorem_words"`
+}
+
+func init() {
+	fakeCmd.Flags().StringVarP(&fakeLocale, "locale", "l", "", "Locale: en_US, en_GB, de_DE, fr_FR, es_ES (default: $LC_ALL/$LANG or en_US)")
+	fakeCmd.Flags().IntVarP(&fakeCount, "count", "n", 1, "Number of values to generate")
+	fakeCmd.Flags().StringVarP(&fakeOutput, "output", "o", "text", "Output format: text, lines, ndjson, json, csv")
+	fakeCmd.Flags().BoolVarP(&fakeUnique, "unique", "u", false, "Guarantee no duplicates within the batch")
+	fakeCmd.Flags().StringVar(&fakeSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
+	rootCmd.AddCommand(fakeCmd)
+}
+
+func runFake(cmd *cobra.Command, args []string) error {
+	if fakeCount < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+
+	if fakeSeed != "" {
+		useSeededSource(fakeSeed)
+		defer useCryptoSource()
+	}
+
+	locale := fakeLocale
+	if locale == "" {
+		locale = defaultFakeLocale()
+	}
+	gen, err := newFakeGenerator(strings.ToLower(args[0]), locale)
+	if err != nil {
+		return err
+	}
+
+	values, err := collectRandomValues(gen, fakeCount, fakeUnique)
+	if err != nil {
+		return err
+	}
+	return writeRandomValues(gen, values, fakeOutput)
+}
+
+// newFakeGenerator returns a generator for one kind of fake value.
+func newFakeGenerator(kind, locale string) (randomGenerator, error) {
+	known := false
+	for _, name := range fakeKindNames {
+		known = known || name == kind
+	}
+	if !known {
+		return randomGenerator{}, fmt.Errorf("unsupported kind: %s (supported: %s)", kind, strings.Join(fakeKindNames, ", "))
+	}
+
+	f, err := newFaker(locale)
+	if err != nil {
+		return randomGenerator{}, err
+	}
+	return randomGenerator{
+		name:   "fake " + kind,
+		label:  fmt.Sprintf("Fake %s (%s)", kind, f.locale),
+		column: kind,
+		next:   func() (string, error) { return f.generate(kind) },
+	}, nil
+}
+
+// defaultFakeLocale picks the locale from the environment, falling back to
+// en_US when it is unset or unsupported.
+func defaultFakeLocale() string {
+	for _, env := range []string{"LC_ALL", "LANG"} {
+		value, _, _ := strings.Cut(os.Getenv(env), ".")
+		if locale, err := resolveFakeLocale(value); err == nil {
+			return locale
+		}
+	}
+	return "en_US"
+}
+
+// fakeLocales lists the embedded locales.
+func fakeLocales() []string {
+	entries, _ := fakeData.ReadDir("data/fake")
+	var locales []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if name != "common" {
+			locales = append(locales, name)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// resolveFakeLocale matches a locale such as "de-de" or "de_DE"
+// case-insensitively against the embedded locales.
+func resolveFakeLocale(locale string) (string, error) {
+	normalized := strings.ReplaceAll(locale, "-", "_")
+	for _, name := range fakeLocales() {
+		if strings.EqualFold(name, normalized) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported locale: %s (supported: %s)", locale, strings.Join(fakeLocales(), ", "))
+}
+
+// faker draws fake values for one locale. The first randomness error is
+// kept in err so the builders can compose values without checking each
+// draw.
+type faker struct {
+	locale string
+	corpus fakeCorpus
+	common fakeCommon
+	err    error
+}
+
+func newFaker(locale string) (*faker, error) {
+	name, err := resolveFakeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	f := &faker{locale: name}
+	if err := loadFakeJSON(name, &f.corpus); err != nil {
+		return nil, err
+	}
+	if err := loadFakeJSON("common", &f.common); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func loadFakeJSON(name string, v interface{}) error {
+	data, err := fakeData.ReadFile(path.Join("data/fake", name+".json"))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("corrupt fake corpus %s: %w", name, err)
+	}
+	return nil
+}
+
+// generate returns one value of the given kind.
+func (f *faker) generate(kind string) (string, error) {
+	f.err = nil
+	value := f.value(kind)
+	return value, f.err
+}
+
+func (f *faker) value(kind string) string {
+	c := f.corpus
+	switch kind {
+	case "name":
+		return f.value("first-name") + " " + f.value("last-name")
+	case "first-name":
+		return f.pick(c.FirstNames)
+	case "last-name":
+		return f.pick(c.LastNames)
+	case "username":
+		name := asciiSlug(f.value("first-name")) + f.pick([]string{".", "_", ""}) + asciiSlug(f.value("last-name"))
+		if f.intn(2) == 1 {
+			name += f.pattern("##")
+		}
+		return name
+	case "email":
+		local := asciiSlug(f.value("first-name")) + "." + asciiSlug(f.value("last-name"))
+		if f.intn(3) == 0 {
+			local += f.pattern("%#")
+		}
+		return local + "@" + f.pick(f.common.EmailDomains)
+	case "company":
+		return f.expand(f.pick(c.CompanyFormat))
+	case "company-suffix":
+		return f.pick(c.CompanySuffixes)
+	case "address":
+		return f.expand(c.AddressFormat)
+	case "street":
+		return f.expand(c.StreetFormat)
+	case "street-name":
+		return f.pick(c.StreetNames)
+	case "building-number":
+		return f.pattern(f.pick(c.BuildingNumber))
+	case "city":
+		return f.pick(c.Cities)
+	case "state":
+		return f.pick(c.States)
+	case "postcode":
+		return f.pattern(f.pick(c.Postcode))
+	case "phone":
+		return f.pattern(f.pick(c.Phone))
+	case "domain":
+		if f.intn(2) == 0 {
+			return asciiSlug(f.value("last-name")) + "." + f.pick(f.common.TLDs)
+		}
+		return f.pick(f.common.DomainWords) + "-" + f.pick(f.common.DomainWords) + "." + f.pick(f.common.TLDs)
+	case "url":
+		return "https://www." + f.value("domain") + "/" + f.pick(f.common.URLPaths)
+	case "ipv4":
+		return fmt.Sprintf("%s.%d", f.pick([]string{"192.0.2", "198.51.100", "203.0.113"}), 1+f.intn(254))
+	case "ipv6":
+		ip := make(net.IP, 16)
+		copy(ip, []byte{0x20, 0x01, 0x0d, 0xb8})
+		for i := 4; i < 16; i++ {
+			ip[i] = byte(f.intn(256))
+		}
+		return ip.String()
+	case "user-agent":
+		return f.pick(f.common.UserAgents)
+	case "lorem":
+		words := make([]string, 6+f.intn(7))
+		for i := range words {
+			words[i] = f.pick(f.common.LoremWords)
+		}
+		return titleWord(strings.Join(words, " ")) + "."
+	}
+	return ""
+}
+
+func (f *faker) intn(n int) int {
+	if f.err != nil {
+		return 0
+	}
+	i, err := randomIndex(n)
+	if err != nil {
+		f.err = err
+	}
+	return i
+}
+
+func (f *faker) pick(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return list[f.intn(len(list))]
+}
+
+// pattern replaces # with a digit, % with a non-zero digit, and ? with an
+// uppercase letter.
+func (f *faker) pattern(p string) string {
+	var b strings.Builder
+	for _, r := range p {
+		switch r {
+		case '#':
+			b.WriteByte(digitChars[f.intn(10)])
+		case '%':
+			b.WriteByte(digitChars[1+f.intn(9)])
+		case '?':
+			b.WriteByte(upperChars[f.intn(26)])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// expand replaces each {kind} in format with a value of that kind.
+func (f *faker) expand(format string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(format, "{")
+		end := strings.Index(format, "}")
+		if start < 0 || end < start {
+			b.WriteString(format)
+			return b.String()
+		}
+		b.WriteString(format[:start])
+		b.WriteString(f.value(format[start+1 : end]))
+		format = format[end+1:]
+	}
+}
+
+// asciiFolder transliterates the accented letters used by the corpora.
+var asciiFolder = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"à", "a", "á", "a", "â", "a", "ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"í", "i", "î", "i", "ï", "i", "ñ", "n", "ó", "o", "ô", "o", "ú", "u", "ù", "u", "û", "u",
+)
+
+// asciiSlug lowercases s, transliterates accents, and drops anything that is
+// not an ASCII letter or digit, for use in usernames, emails, and domains.
+func asciiSlug(s string) string {
+	folded := asciiFolder.Replace(strings.ToLower(s))
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, folded)
+}
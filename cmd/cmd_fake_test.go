@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestFakeCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		flags    map[string]string
+		validate func(string) bool
+		wantErr  bool
+	}{
+		{
+			name:  "single name is labelled with the locale",
+			args:  []string{"name"},
+			flags: map[string]string{"locale": "de-de"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Fake name \(de_DE\): \S+ \S+$`).MatchString(output)
+			},
+		},
+		{
+			name:  "emails as CSV",
+			args:  []string{"email"},
+			flags: map[string]string{"count": "5", "output": "csv"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				if len(lines) != 6 || lines[0] != "email" {
+					return false
+				}
+				for _, line := range lines[1:] {
+					if !regexp.MustCompile(`^[a-z]+\.[a-z]+(\d\d)?@example\.(com|org|net)$`).MatchString(line) {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name:  "domains and URLs use reserved TLDs",
+			args:  []string{"url"},
+			flags: map[string]string{"count": "20", "output": "ndjson"},
+			validate: func(output string) bool {
+				for _, line := range strings.Split(output, "\n") {
+					if !regexp.MustCompile(`^https://www\.[a-z-]+\.(example|test)/[a-z]*$`).MatchString(line) {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name:  "seeded output is reproducible",
+			args:  []string{"name"},
+			flags: map[string]string{"seed": "fixtures", "count": "3", "output": "lines"},
+			validate: func(output string) bool {
+				return output == "William Baker\nNoah Taylor\nElizabeth Robinson"
+			},
+		},
+		{
+			name:    "unsupported kind",
+			args:    []string{"ssn"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported locale",
+			args:    []string{"name"},
+			flags:   map[string]string{"locale": "xx_XX"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags to default values
+			fakeLocale = "en_US"
+			fakeCount = 1
+			fakeOutput = "text"
+			fakeUnique = false
+			fakeSeed = ""
+
+			cmd := &cobra.Command{
+				Use:  "fake <kind>",
+				Args: cobra.ExactArgs(1),
+				RunE: runFake,
+			}
+			cmd.Flags().StringVarP(&fakeLocale, "locale", "l", "en_US", "Locale")
+			cmd.Flags().IntVarP(&fakeCount, "count", "n", 1, "Number of values to generate")
+			cmd.Flags().StringVarP(&fakeOutput, "output", "o", "text", "Output format: text, lines, ndjson, json, csv")
+			cmd.Flags().BoolVarP(&fakeUnique, "unique", "u", false, "Guarantee no duplicates within the batch")
+			cmd.Flags().StringVar(&fakeSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
+
+			for flag, value := range tt.flags {
+				cmd.Flags().Set(flag, value)
+			}
+
+			old := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := cmd.RunE(cmd, tt.args)
+
+			w.Close()
+			os.Stdout = old
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := strings.TrimSpace(buf.String())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if !tt.validate(output) {
+					t.Errorf("Output validation failed for: %q", output)
+				}
+			}
+		})
+	}
+}
+
+func TestFakeKinds(t *testing.T) {
+	for _, locale := range fakeLocales() {
+		f, err := newFaker(locale)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", locale, err)
+		}
+		for _, kind := range fakeKindNames {
+			for i := 0; i < 20; i++ {
+				value, err := f.generate(kind)
+				if err != nil {
+					t.Fatalf("%s/%s: unexpected error: %v", locale, kind, err)
+				}
+				if value == "" || strings.ContainsAny(value, "{}#%") {
+					t.Errorf("%s/%s: unexpanded or empty value %q", locale, kind, value)
+				}
+			}
+		}
+	}
+}
+
+func TestFakeSafeValues(t *testing.T) {
+	f, err := newFaker("en_US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc4 []*net.IPNet
+	for _, cidr := range []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		doc4 = append(doc4, network)
+	}
+	_, doc6, _ := net.ParseCIDR("2001:db8::/32")
+	for i := 0; i < 50; i++ {
+		v4, _ := f.generate("ipv4")
+		ip := net.ParseIP(v4)
+		if ip == nil || !(doc4[0].Contains(ip) || doc4[1].Contains(ip) || doc4[2].Contains(ip)) {
+			t.Errorf("Expected a documentation IPv4 address, got %s", v4)
+		}
+		v6, _ := f.generate("ipv6")
+		if ip := net.ParseIP(v6); ip == nil || !doc6.Contains(ip) {
+			t.Errorf("Expected a documentation IPv6 address, got %s", v6)
+		}
+		phone, _ := f.generate("phone")
+		if !strings.Contains(phone, "555-01") {
+			t.Errorf("Expected a fictional 555-01xx number, got %s", phone)
+		}
+	}
+}
+
+func TestFakeHelpers(t *testing.T) {
+	for input, want := range map[string]string{"Müller": "mueller", "Élodie": "elodie", "Núñez": "nunez", "d'Arc Smith": "darcsmith"} {
+		if got := asciiSlug(input); got != want {
+			t.Errorf("asciiSlug(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for input, want := range map[string]string{"de_DE": "de_DE", "fr-fr": "fr_FR", "EN_gb": "en_GB"} {
+		if got, err := resolveFakeLocale(input); err != nil || got != want {
+			t.Errorf("resolveFakeLocale(%q) = %q, %v; want %s", input, got, err, want)
+		}
+	}
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANG", "es_ES.UTF-8")
+	if got := defaultFakeLocale(); got != "es_ES" {
+		t.Errorf("Expected locale from $LANG, got %s", got)
+	}
+	t.Setenv("LANG", "C")
+	if got := defaultFakeLocale(); got != "en_US" {
+		t.Errorf("Expected en_US fallback, got %s", got)
+	}
+}
@@ -44,16 +44,9 @@ func runHash(cmd *cobra.Command, args []string) error {
 		data = []byte(input)
 	}
 
-	var hash string
-	switch strings.ToLower(hashType) {
-	case "md5":
-		hash = fmt.Sprintf("%x", md5.Sum(data))
-	case "sha1":
-		hash = fmt.Sprintf("%x", sha1.Sum(data))
-	case "sha256":
-		hash = fmt.Sprintf("%x", sha256.Sum256(data))
-	default:
-		return fmt.Errorf("unsupported hash type: %s (supported: md5, sha1, sha256)", hashType)
+	hash, err := computeHash(hashType, data)
+	if err != nil {
+		return err
 	}
 
 	if hashFromFile {
@@ -64,3 +57,17 @@ func runHash(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// computeHash returns the hex digest of data for the named algorithm.
+func computeHash(algorithm string, data []byte) (string, error) {
+	switch strings.ToLower(algorithm) {
+	case "md5":
+		return fmt.Sprintf("%x", md5.Sum(data)), nil
+	case "sha1":
+		return fmt.Sprintf("%x", sha1.Sum(data)), nil
+	case "sha256":
+		return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+	default:
+		return "", fmt.Errorf("unsupported hash type: %s (supported: md5, sha1, sha256)", algorithm)
+	}
+}
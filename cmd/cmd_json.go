@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -11,52 +19,90 @@ import (
 var jsonCmd = &cobra.Command{
 	Use:   "json [json-string|file]",
 	Short: "Process JSON data",
-	Long:  `Pretty print, minify, or validate JSON data from string or file.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runJSON,
+	Long: `Pretty print, minify, or validate JSON data from string or file.
+
+MessagePack and CBOR input (a binary file or base64 string) can be rendered
+as JSON with --from, and JSON can be converted to either format with --to.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJSON,
 }
 
 var (
-	jsonMinify   bool
-	jsonValidate bool
-	jsonFromFile bool
+	jsonMinify     bool
+	jsonValidate   bool
+	jsonFromFile   bool
+	jsonFrom       string
+	jsonTo         string
+	jsonOutputFile string
 )
 
+// jsonFormatNames maps the supported --from/--to values to display names.
+var jsonFormatNames = map[string]string{
+	"json":    "JSON",
+	"msgpack": "MessagePack",
+	"cbor":    "CBOR",
+}
+
 func init() {
 	jsonCmd.Flags().BoolVarP(&jsonMinify, "minify", "m", false, "Minify JSON instead of pretty printing")
 	jsonCmd.Flags().BoolVarP(&jsonValidate, "validate", "v", false, "Only validate JSON, don't output")
 	jsonCmd.Flags().BoolVarP(&jsonFromFile, "file", "f", false, "Read JSON from file instead of string")
+	jsonCmd.Flags().StringVar(&jsonFrom, "from", "json", "Input format: json, msgpack, cbor")
+	jsonCmd.Flags().StringVar(&jsonTo, "to", "json", "Output format: json, msgpack, cbor")
+	jsonCmd.Flags().StringVarP(&jsonOutputFile, "output", "o", "", "Write binary output to file instead of printing base64")
 	rootCmd.AddCommand(jsonCmd)
 }
 
 func runJSON(cmd *cobra.Command, args []string) error {
 	input := args[0]
-	var jsonData []byte
+	from := strings.ToLower(jsonFrom)
+	to := strings.ToLower(jsonTo)
+	if _, ok := jsonFormatNames[from]; !ok {
+		return fmt.Errorf("unsupported input format: %s (supported: json, msgpack, cbor)", jsonFrom)
+	}
+	if _, ok := jsonFormatNames[to]; !ok {
+		return fmt.Errorf("unsupported output format: %s (supported: json, msgpack, cbor)", jsonTo)
+	}
+
+	var data interface{}
 	var err error
+	if from == "json" {
+		var jsonData []byte
+		if jsonFromFile {
+			jsonData, err = os.ReadFile(input)
+			if err != nil {
+				return fmt.Errorf("failed to read file %s: %w", input, err)
+			}
+		} else {
+			jsonData = []byte(input)
+		}
 
-	if jsonFromFile {
-		jsonData, err = os.ReadFile(input)
+		// Binary targets need to tell integers from floats.
+		data, err = parseJSON(jsonData, to != "json")
 		if err != nil {
-			return fmt.Errorf("failed to read file %s: %w", input, err)
+			return err
 		}
 	} else {
-		jsonData = []byte(input)
-	}
-
-	var data interface{}
-	if unmarshalErr := json.Unmarshal(jsonData, &data); unmarshalErr != nil {
-		return fmt.Errorf("invalid JSON: %w", unmarshalErr)
+		data, err = decodeBinaryJSON(from, input)
+		if err != nil {
+			return err
+		}
 	}
 
 	if jsonValidate {
+		name := jsonFormatNames[from]
 		if jsonFromFile {
-			fmt.Printf("✓ Valid JSON file: %s\n", input)
+			fmt.Printf("✓ Valid %s file: %s\n", name, input)
 		} else {
-			fmt.Println("✓ Valid JSON")
+			fmt.Printf("✓ Valid %s\n", name)
 		}
 		return nil
 	}
 
+	if to != "json" {
+		return writeBinaryJSON(to, data)
+	}
+
 	var output []byte
 	if jsonMinify {
 		output, err = json.Marshal(data)
@@ -75,12 +121,209 @@ func runJSON(cmd *cobra.Command, args []string) error {
 		operation = "Minified"
 	}
 
-	if jsonFromFile {
+	switch {
+	case jsonFromFile:
 		fmt.Printf("%s JSON from %s:\n", operation, input)
-	} else {
+	case from != "json":
+		fmt.Printf("%s JSON from %s:\n", operation, jsonFormatNames[from])
+	default:
 		fmt.Printf("%s JSON:\n", operation)
 	}
 	fmt.Println(string(output))
 
 	return nil
 }
+
+// parseJSON unmarshals a single JSON document. With preserveNumbers set,
+// numbers are kept as json.Number so integer and float literals stay distinct.
+func parseJSON(jsonData []byte, preserveNumbers bool) (interface{}, error) {
+	var data interface{}
+	if !preserveNumbers {
+		if err := json.Unmarshal(jsonData, &data); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after top-level value")
+	}
+	return data, nil
+}
+
+// decodeBinaryJSON reads MessagePack or CBOR from a file or base64 string.
+func decodeBinaryJSON(format, input string) (interface{}, error) {
+	encoding := "base64"
+	if jsonFromFile {
+		encoding = "raw"
+	}
+	raw, err := readBinaryInput(input, encoding, jsonFromFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if format == "msgpack" {
+		data, err = decodeMsgpack(raw)
+	} else {
+		data, err = decodeCBOR(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", jsonFormatNames[format], err)
+	}
+	return data, nil
+}
+
+// writeBinaryJSON encodes data as MessagePack or CBOR, writing raw bytes to
+// the --output file or printing them as base64.
+func writeBinaryJSON(format string, data interface{}) error {
+	var out []byte
+	var err error
+	if format == "msgpack" {
+		out, err = encodeMsgpack(data)
+	} else {
+		out, err = encodeCBOR(data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", jsonFormatNames[format], err)
+	}
+
+	if jsonOutputFile != "" {
+		if err := os.WriteFile(jsonOutputFile, out, 0o644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", jsonOutputFile, err)
+		}
+		fmt.Printf("Wrote %d bytes of %s to %s\n", len(out), jsonFormatNames[format], jsonOutputFile)
+		return nil
+	}
+
+	fmt.Printf("Converted JSON to %s (base64):\n", jsonFormatNames[format])
+	fmt.Println(base64.StdEncoding.EncodeToString(out))
+	return nil
+}
+
+// Kinds of JSON numbers as classified by classifyJSONNumber.
+const (
+	jsonInt = iota
+	jsonUint
+	jsonFloat
+	// jsonBigInt integers fit neither int64 nor uint64; callers read them
+	// with bigJSONInteger.
+	jsonBigInt
+)
+
+// classifyJSONNumber decides whether a literal is an integer (fitting int64
+// or uint64, or wider) or a float. Literals with a fraction or exponent are
+// floats.
+func classifyJSONNumber(n json.Number) (int64, uint64, float64, int, error) {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, 0, 0, jsonInt, nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return 0, u, 0, jsonUint, nil
+		}
+		if _, ok := bigJSONInteger(n); ok {
+			return 0, 0, 0, jsonBigInt, nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("number %s out of range", s)
+	}
+	return 0, 0, f, jsonFloat, nil
+}
+
+// bigJSONInteger parses an integer literal of any size.
+func bigJSONInteger(n json.Number) (*big.Int, bool) {
+	return new(big.Int).SetString(n.String(), 10)
+}
+
+func intNumber(i int64) json.Number {
+	return json.Number(strconv.FormatInt(i, 10))
+}
+
+func uintNumber(u uint64) json.Number {
+	return json.Number(strconv.FormatUint(u, 10))
+}
+
+// floatNumber formats a decoded float so it reads back as a float, e.g.
+// 1.0 rather than 1.
+func floatNumber(f float64, bitSize int) (json.Number, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("%v cannot be represented in JSON", f)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return json.Number(s), nil
+}
+
+// mapKeyString renders a decoded map key as a JSON object key.
+func mapKeyString(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	out, err := json.Marshal(k)
+	if err != nil {
+		return fmt.Sprint(k)
+	}
+	return string(out)
+}
+
+// maxBinaryDepth bounds nesting when decoding binary formats.
+const maxBinaryDepth = 512
+
+var errBinaryTruncated = errors.New("unexpected end of input")
+
+// binaryDecoder is a cursor over a byte slice shared by the MessagePack and
+// CBOR decoders.
+type binaryDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *binaryDecoder) byte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errBinaryTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *binaryDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errBinaryTruncated
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// uintN reads a big-endian unsigned integer of size bytes.
+func (d *binaryDecoder) uintN(size int) (uint64, error) {
+	b, err := d.bytes(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *binaryDecoder) str(n int) (interface{}, error) {
+	b, err := d.bytes(uint64(n))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// CBOR major types (RFC 8949 section 3.1).
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// cborBreak terminates indefinite-length items.
+const cborBreak = 0xff
+
+// encodeCBOR serializes a value produced by parseJSON with number
+// preservation into CBOR using preferred (shortest) argument encodings.
+func encodeCBOR(v interface{}) ([]byte, error) {
+	var buf []byte
+	if err := appendCBOR(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func appendCBOR(buf *[]byte, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		*buf = append(*buf, 0xf6)
+	case bool:
+		if val {
+			*buf = append(*buf, 0xf5)
+		} else {
+			*buf = append(*buf, 0xf4)
+		}
+	case json.Number:
+		i, u, f, kind, err := classifyJSONNumber(val)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case jsonInt:
+			if i < 0 {
+				appendCBORHead(buf, cborNegInt, uint64(-(i + 1)))
+			} else {
+				appendCBORHead(buf, cborUint, uint64(i))
+			}
+		case jsonUint:
+			appendCBORHead(buf, cborUint, u)
+		case jsonBigInt:
+			appendCBORBignum(buf, val)
+		default:
+			*buf = append(*buf, 0xfb)
+			*buf = binary.BigEndian.AppendUint64(*buf, math.Float64bits(f))
+		}
+	case string:
+		appendCBORHead(buf, cborText, uint64(len(val)))
+		*buf = append(*buf, val...)
+	case []interface{}:
+		appendCBORHead(buf, cborArray, uint64(len(val)))
+		for _, item := range val {
+			if err := appendCBOR(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		appendCBORHead(buf, cborMap, uint64(len(val)))
+		for _, key := range sortedKeys(val) {
+			if err := appendCBOR(buf, key); err != nil {
+				return err
+			}
+			if err := appendCBOR(buf, val[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value type %T", v)
+	}
+	return nil
+}
+
+// appendCBORBignum writes an integer too wide for a CBOR integer as a
+// positive (tag 2) or negative (tag 3) bignum, which holds -1 - n for n < 0.
+func appendCBORBignum(buf *[]byte, n json.Number) {
+	b, _ := bigJSONInteger(n)
+	tag := uint64(2)
+	if b.Sign() < 0 {
+		tag = 3
+		b.Neg(b.Add(b, big.NewInt(1)))
+	}
+	appendCBORHead(buf, cborTag, tag)
+	raw := b.Bytes()
+	appendCBORHead(buf, cborBytes, uint64(len(raw)))
+	*buf = append(*buf, raw...)
+}
+
+func appendCBORHead(buf *[]byte, major byte, arg uint64) {
+	m := major << 5
+	switch {
+	case arg < 24:
+		*buf = append(*buf, m|byte(arg))
+	case arg <= math.MaxUint8:
+		*buf = append(*buf, m|24, byte(arg))
+	case arg <= math.MaxUint16:
+		*buf = append(*buf, m|25)
+		*buf = binary.BigEndian.AppendUint16(*buf, uint16(arg))
+	case arg <= math.MaxUint32:
+		*buf = append(*buf, m|26)
+		*buf = binary.BigEndian.AppendUint32(*buf, uint32(arg))
+	default:
+		*buf = append(*buf, m|27)
+		*buf = binary.BigEndian.AppendUint64(*buf, arg)
+	}
+}
+
+// decodeCBOR parses a single CBOR data item into JSON-compatible Go values.
+// Byte strings are rendered as base64, bignums as integers, and other tags
+// are unwrapped to their content.
+func decodeCBOR(data []byte) (interface{}, error) {
+	d := &binaryDecoder{data: data}
+	v, err := d.cborValue(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected trailing data at offset %d", d.pos)
+	}
+	return v, nil
+}
+
+// cborHead reads an initial byte and its argument. For indefinite-length
+// items indefinite is true and arg is zero.
+func (d *binaryDecoder) cborHead() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	b, err := d.byte()
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = b>>5, b&0x1f
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		arg, err = d.uintN(1 << (info - 24))
+	case info == 31:
+		indefinite = true
+	default:
+		err = fmt.Errorf("invalid CBOR additional info %d at offset %d", info, d.pos-1)
+	}
+	return major, info, arg, indefinite, err
+}
+
+func (d *binaryDecoder) cborValue(depth int) (interface{}, error) {
+	if depth > maxBinaryDepth {
+		return nil, fmt.Errorf("nesting exceeds %d levels", maxBinaryDepth)
+	}
+	major, info, arg, indefinite, err := d.cborHead()
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == cborUint || major == cborNegInt || major == cborTag) {
+		return nil, fmt.Errorf("invalid indefinite length for major type %d", major)
+	}
+
+	switch major {
+	case cborUint:
+		return uintNumber(arg), nil
+	case cborNegInt:
+		if arg > math.MaxInt64 {
+			n := new(big.Int).SetUint64(arg)
+			return json.Number(n.Neg(n.Add(n, big.NewInt(1))).String()), nil
+		}
+		return intNumber(-1 - int64(arg)), nil
+	case cborBytes, cborText:
+		raw, err := d.cborString(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborBytes {
+			return base64.StdEncoding.EncodeToString(raw), nil
+		}
+		return string(raw), nil
+	case cborArray:
+		items := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			v, err := d.cborValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case cborMap:
+		m := map[string]interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			k, err := d.cborValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.cborValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[mapKeyString(k)] = v
+		}
+		return m, nil
+	case cborTag:
+		content, err := d.cborValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		// Tags 2 and 3 are positive and negative bignums.
+		if arg == 2 || arg == 3 {
+			s, ok := content.(string)
+			if !ok {
+				return nil, fmt.Errorf("bignum tag %d must wrap a byte string", arg)
+			}
+			raw, _ := base64.StdEncoding.DecodeString(s)
+			n := new(big.Int).SetBytes(raw)
+			if arg == 3 {
+				n.Neg(n.Add(n, big.NewInt(1)))
+			}
+			return json.Number(n.String()), nil
+		}
+		return content, nil
+	default:
+		return d.cborSimple(info, arg)
+	}
+}
+
+// cborString reads a definite or indefinite (chunked) byte or text string.
+func (d *binaryDecoder) cborString(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.bytes(arg)
+	}
+	var out []byte
+	for !d.atBreak() {
+		chunkMajor, _, n, chunkIndefinite, err := d.cborHead()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkIndefinite {
+			return nil, fmt.Errorf("invalid chunk in indefinite-length string at offset %d", d.pos)
+		}
+		chunk, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+func (d *binaryDecoder) cborSimple(info byte, arg uint64) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return floatNumber(halfToFloat(uint16(arg)), 32)
+	case 26:
+		return floatNumber(float64(math.Float32frombits(uint32(arg))), 32)
+	case 27:
+		return floatNumber(math.Float64frombits(arg), 64)
+	case 31:
+		return nil, fmt.Errorf("unexpected break at offset %d", d.pos-1)
+	}
+	return nil, fmt.Errorf("unsupported CBOR simple value %d", arg)
+}
+
+// atBreak consumes a break marker if one is next.
+func (d *binaryDecoder) atBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// halfToFloat converts an IEEE 754 half-precision value to float64.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
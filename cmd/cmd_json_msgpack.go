@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// encodeMsgpack serializes a value produced by parseJSON with number
+// preservation into MessagePack, using the smallest encoding for each value.
+func encodeMsgpack(v interface{}) ([]byte, error) {
+	var buf []byte
+	if err := appendMsgpack(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func appendMsgpack(buf *[]byte, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		*buf = append(*buf, 0xc0)
+	case bool:
+		if val {
+			*buf = append(*buf, 0xc3)
+		} else {
+			*buf = append(*buf, 0xc2)
+		}
+	case json.Number:
+		i, u, f, kind, err := classifyJSONNumber(val)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case jsonInt:
+			appendMsgpackInt(buf, i)
+		case jsonUint:
+			appendMsgpackUint(buf, u)
+		case jsonBigInt:
+			return fmt.Errorf("integer %s out of range: MessagePack integers are limited to 64 bits", val)
+		default:
+			*buf = append(*buf, 0xcb)
+			*buf = binary.BigEndian.AppendUint64(*buf, math.Float64bits(f))
+		}
+	case string:
+		n := len(val)
+		switch {
+		case n < 32:
+			*buf = append(*buf, 0xa0|byte(n))
+		case n <= math.MaxUint8:
+			*buf = append(*buf, 0xd9, byte(n))
+		case n <= math.MaxUint16:
+			*buf = append(*buf, 0xda)
+			*buf = binary.BigEndian.AppendUint16(*buf, uint16(n))
+		default:
+			*buf = append(*buf, 0xdb)
+			*buf = binary.BigEndian.AppendUint32(*buf, uint32(n))
+		}
+		*buf = append(*buf, val...)
+	case []interface{}:
+		appendMsgpackHeader(buf, len(val), 0x90, 0xdc, 0xdd)
+		for _, item := range val {
+			if err := appendMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		appendMsgpackHeader(buf, len(val), 0x80, 0xde, 0xdf)
+		for _, key := range sortedKeys(val) {
+			if err := appendMsgpack(buf, key); err != nil {
+				return err
+			}
+			if err := appendMsgpack(buf, val[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value type %T", v)
+	}
+	return nil
+}
+
+func appendMsgpackHeader(buf *[]byte, n int, fix, b16, b32 byte) {
+	switch {
+	case n < 16:
+		*buf = append(*buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		*buf = append(*buf, b16)
+		*buf = binary.BigEndian.AppendUint16(*buf, uint16(n))
+	default:
+		*buf = append(*buf, b32)
+		*buf = binary.BigEndian.AppendUint32(*buf, uint32(n))
+	}
+}
+
+func appendMsgpackInt(buf *[]byte, i int64) {
+	switch {
+	case i >= 0:
+		appendMsgpackUint(buf, uint64(i))
+	case i >= -32:
+		*buf = append(*buf, byte(i))
+	case i >= math.MinInt8:
+		*buf = append(*buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		*buf = append(*buf, 0xd1)
+		*buf = binary.BigEndian.AppendUint16(*buf, uint16(i))
+	case i >= math.MinInt32:
+		*buf = append(*buf, 0xd2)
+		*buf = binary.BigEndian.AppendUint32(*buf, uint32(i))
+	default:
+		*buf = append(*buf, 0xd3)
+		*buf = binary.BigEndian.AppendUint64(*buf, uint64(i))
+	}
+}
+
+func appendMsgpackUint(buf *[]byte, u uint64) {
+	switch {
+	case u < 128:
+		*buf = append(*buf, byte(u))
+	case u <= math.MaxUint8:
+		*buf = append(*buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		*buf = append(*buf, 0xcd)
+		*buf = binary.BigEndian.AppendUint16(*buf, uint16(u))
+	case u <= math.MaxUint32:
+		*buf = append(*buf, 0xce)
+		*buf = binary.BigEndian.AppendUint32(*buf, uint32(u))
+	default:
+		*buf = append(*buf, 0xcf)
+		*buf = binary.BigEndian.AppendUint64(*buf, u)
+	}
+}
+
+// decodeMsgpack parses a single MessagePack value into JSON-compatible Go
+// values. Numbers are returned as json.Number so integers stay integers.
+// Binary data is rendered as base64 and extension types as objects.
+func decodeMsgpack(data []byte) (interface{}, error) {
+	d := &binaryDecoder{data: data}
+	v, err := d.msgpackValue(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected trailing data at offset %d", d.pos)
+	}
+	return v, nil
+}
+
+func (d *binaryDecoder) msgpackValue(depth int) (interface{}, error) {
+	if depth > maxBinaryDepth {
+		return nil, fmt.Errorf("nesting exceeds %d levels", maxBinaryDepth)
+	}
+	b, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return intNumber(int64(b)), nil
+	case b >= 0xe0:
+		return intNumber(int64(int8(b))), nil
+	case b&0xf0 == 0x80:
+		return d.msgpackMap(int(b&0x0f), depth)
+	case b&0xf0 == 0x90:
+		return d.msgpackArray(int(b&0x0f), depth)
+	case b&0xe0 == 0xa0:
+		return d.str(int(b & 0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uintN(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(raw), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uintN(1 << (b - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.msgpackExt(n)
+	case 0xca:
+		u, err := d.uintN(4)
+		if err != nil {
+			return nil, err
+		}
+		return floatNumber(float64(math.Float32frombits(uint32(u))), 32)
+	case 0xcb:
+		u, err := d.uintN(8)
+		if err != nil {
+			return nil, err
+		}
+		return floatNumber(math.Float64frombits(u), 64)
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uintN(1 << (b - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return uintNumber(u), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		u, err := d.uintN(size)
+		if err != nil {
+			return nil, err
+		}
+		shift := uint(64 - 8*size)
+		return intNumber(int64(u<<shift) >> shift), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.msgpackExt(1 << (b - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uintN(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uintN(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.msgpackArray(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := d.uintN(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.msgpackMap(int(n), depth)
+	}
+	return nil, fmt.Errorf("invalid MessagePack type byte 0x%02x at offset %d", b, d.pos-1)
+}
+
+func (d *binaryDecoder) msgpackArray(n, depth int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errBinaryTruncated
+	}
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := d.msgpackValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func (d *binaryDecoder) msgpackMap(n, depth int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errBinaryTruncated
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.msgpackValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.msgpackValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		m[mapKeyString(k)] = v
+	}
+	return m, nil
+}
+
+// msgpackExt decodes an extension value. The predefined timestamp type (-1)
+// becomes an RFC 3339 string; anything else keeps its type and payload.
+func (d *binaryDecoder) msgpackExt(n uint64) (interface{}, error) {
+	t, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(t) == -1 {
+		var ts time.Time
+		switch len(raw) {
+		case 4:
+			ts = time.Unix(int64(binary.BigEndian.Uint32(raw)), 0)
+		case 8:
+			v := binary.BigEndian.Uint64(raw)
+			ts = time.Unix(int64(v&0x3ffffffff), int64(v>>34))
+		case 12:
+			ts = time.Unix(int64(binary.BigEndian.Uint64(raw[4:])), int64(binary.BigEndian.Uint32(raw)))
+		default:
+			return nil, fmt.Errorf("invalid timestamp extension length %d", len(raw))
+		}
+		return ts.UTC().Format(time.RFC3339Nano), nil
+	}
+	return map[string]interface{}{
+		"type": intNumber(int64(int8(t))),
+		"data": base64.StdEncoding.EncodeToString(raw),
+	}, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,725 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+var jsonGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate random JSON documents that conform to a JSON Schema",
+	Long: `Walk a JSON Schema and produce random instances that validate against it.
+
+Supported keywords: type (including type lists), enum, const, properties,
+required, items, prefixItems, minItems, maxItems, uniqueItems, minLength,
+maxLength, pattern, format, minimum, maximum, exclusiveMinimum,
+exclusiveMaximum, multipleOf, allOf, anyOf, oneOf, and local $ref pointers
+(#/definitions/..., #/$defs/...). Optional properties are included at
+random; additional properties are never generated.
+
+Patterns use Go (RE2) syntax. Known formats (date-time, date, time, email,
+uri, uuid, ipv4, ipv6, hostname) produce realistic values.
+
+One document is pretty printed; with --count the documents are printed as a
+JSON array, or one per line with --output ndjson.`,
+	Args: cobra.NoArgs,
+	RunE: runJSONGenerate,
+}
+
+var (
+	jsonSchemaFile     string
+	jsonGenerateCount  int
+	jsonGenerateSeed   string
+	jsonGenerateOutput string
+)
+
+// maxSchemaDepth bounds $ref and nesting recursion. Past it only required
+// properties and minimum array lengths are generated.
+const maxSchemaDepth = 8
+
+func init() {
+	jsonGenerateCmd.Flags().StringVarP(&jsonSchemaFile, "schema", "s", "", "JSON Schema file")
+	jsonGenerateCmd.Flags().IntVarP(&jsonGenerateCount, "count", "n", 1, "Number of documents to generate")
+	jsonGenerateCmd.Flags().StringVar(&jsonGenerateSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
+	jsonGenerateCmd.Flags().StringVarP(&jsonGenerateOutput, "output", "o", "json", "Output format: json, ndjson")
+	jsonGenerateCmd.MarkFlagRequired("schema")
+	jsonCmd.AddCommand(jsonGenerateCmd)
+}
+
+func runJSONGenerate(cmd *cobra.Command, args []string) error {
+	if jsonGenerateCount < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+	format := strings.ToLower(jsonGenerateOutput)
+	if format != "json" && format != "ndjson" {
+		return fmt.Errorf("unsupported output format: %s (supported: json, ndjson)", jsonGenerateOutput)
+	}
+
+	data, err := os.ReadFile(jsonSchemaFile)
+	if err != nil {
+		return fmt.Errorf("failed to read schema %s: %w", jsonSchemaFile, err)
+	}
+	schema, err := parseJSON(data, true)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	if jsonGenerateSeed != "" {
+		useSeededSource(jsonGenerateSeed)
+		defer useCryptoSource()
+		fmt.Fprintln(os.Stderr, seedWarning)
+	}
+
+	gen, err := newSchemaGenerator(schema)
+	if err != nil {
+		return err
+	}
+	docs := make([]interface{}, jsonGenerateCount)
+	for i := range docs {
+		if docs[i], err = gen.generate(schema, 0); err != nil {
+			return err
+		}
+	}
+
+	if format == "ndjson" {
+		for _, doc := range docs {
+			line, err := json.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(line))
+		}
+		return nil
+	}
+
+	var value interface{} = docs
+	if len(docs) == 1 {
+		value = docs[0]
+	}
+	output, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
+
+// schemaGenerator produces instances of a parsed JSON Schema, whose numbers
+// are json.Number values.
+type schemaGenerator struct {
+	root interface{}
+	// patterns caches generators by repeat limit and pattern.
+	patterns map[string]*regexGenerator
+	faker    *faker
+}
+
+func newSchemaGenerator(root interface{}) (*schemaGenerator, error) {
+	f, err := newFaker("en_US")
+	if err != nil {
+		return nil, err
+	}
+	return &schemaGenerator{root: root, patterns: make(map[string]*regexGenerator), faker: f}, nil
+}
+
+// generate returns a random instance of schema.
+func (g *schemaGenerator) generate(schema interface{}, depth int) (interface{}, error) {
+	// Past maxSchemaDepth only required members are generated, so going far
+	// beyond it means a required member recurses forever.
+	if depth > maxSchemaDepth*4 {
+		return nil, fmt.Errorf("schema recursion too deep")
+	}
+
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			return nil, fmt.Errorf("schema false has no valid instances")
+		}
+		return g.generateType("string", map[string]interface{}{}, depth)
+	case map[string]interface{}:
+		resolved, err := g.resolve(s)
+		if err != nil {
+			return nil, err
+		}
+		return g.generateResolved(resolved, depth)
+	default:
+		return nil, fmt.Errorf("invalid schema: expected an object or boolean, got %T", schema)
+	}
+}
+
+// resolve follows $ref and folds allOf, anyOf, and oneOf into a single
+// schema, picking one alternative for anyOf and oneOf.
+func (g *schemaGenerator) resolve(s map[string]interface{}) (map[string]interface{}, error) {
+	for i := 0; ; i++ {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			break
+		}
+		if i > 32 {
+			return nil, fmt.Errorf("$ref cycle at %s", ref)
+		}
+		target, err := g.lookupRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		merged := copySchema(target)
+		for k, v := range s {
+			if k != "$ref" {
+				merged[k] = v
+			}
+		}
+		s = merged
+	}
+
+	if all, ok := s["allOf"].([]interface{}); ok {
+		merged := copySchema(s)
+		delete(merged, "allOf")
+		for _, sub := range all {
+			part, ok := sub.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			part, err := g.resolve(part)
+			if err != nil {
+				return nil, err
+			}
+			mergeSchema(merged, part)
+		}
+		s = merged
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		options, ok := s[key].([]interface{})
+		if !ok || len(options) == 0 {
+			continue
+		}
+		n, err := randomIndex(len(options))
+		if err != nil {
+			return nil, err
+		}
+		merged := copySchema(s)
+		delete(merged, key)
+		if part, ok := options[n].(map[string]interface{}); ok {
+			part, err := g.resolve(part)
+			if err != nil {
+				return nil, err
+			}
+			mergeSchema(merged, part)
+		}
+		s = merged
+	}
+	return s, nil
+}
+
+// lookupRef resolves a local JSON pointer such as "#/$defs/address".
+func (g *schemaGenerator) lookupRef(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %s: only local references are supported", ref)
+	}
+	node := g.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref[1:], "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+		if node, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+	}
+	target, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("$ref %s does not point to a schema object", ref)
+	}
+	return target, nil
+}
+
+func (g *schemaGenerator) generateResolved(s map[string]interface{}, depth int) (interface{}, error) {
+	if value, ok := s["const"]; ok {
+		return value, nil
+	}
+	if values, ok := s["enum"].([]interface{}); ok && len(values) > 0 {
+		n, err := randomIndex(len(values))
+		if err != nil {
+			return nil, err
+		}
+		return values[n], nil
+	}
+
+	typ, err := schemaType(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.generateType(typ, s, depth)
+}
+
+// schemaType picks the instance type: one of a type list, the single
+// declared type, or one inferred from the keywords present.
+func schemaType(s map[string]interface{}) (string, error) {
+	switch t := s["type"].(type) {
+	case string:
+		return t, nil
+	case []interface{}:
+		if len(t) == 0 {
+			return "", fmt.Errorf("empty type list")
+		}
+		n, err := randomIndex(len(t))
+		if err != nil {
+			return "", err
+		}
+		name, _ := t[n].(string)
+		return name, nil
+	}
+	switch {
+	case s["properties"] != nil || s["required"] != nil:
+		return "object", nil
+	case s["items"] != nil || s["prefixItems"] != nil || s["minItems"] != nil:
+		return "array", nil
+	case s["minimum"] != nil || s["maximum"] != nil || s["multipleOf"] != nil:
+		return "number", nil
+	default:
+		return "string", nil
+	}
+}
+
+func (g *schemaGenerator) generateType(typ string, s map[string]interface{}, depth int) (interface{}, error) {
+	switch typ {
+	case "null":
+		return nil, nil
+	case "boolean":
+		n, err := randomIndex(2)
+		return n == 1, err
+	case "integer", "number":
+		return generateSchemaNumber(s, typ == "integer")
+	case "string":
+		return g.generateString(s)
+	case "array":
+		return g.generateArray(s, depth)
+	case "object":
+		return g.generateObject(s, depth)
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", typ)
+	}
+}
+
+func (g *schemaGenerator) generateObject(s map[string]interface{}, depth int) (interface{}, error) {
+	required := make(map[string]bool)
+	var requiredNames []string
+	if list, ok := s["required"].([]interface{}); ok {
+		for _, name := range list {
+			if key, ok := name.(string); ok && !required[key] {
+				required[key] = true
+				requiredNames = append(requiredNames, key)
+			}
+		}
+	}
+
+	obj := make(map[string]interface{})
+	properties, _ := s["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(properties) {
+		if !required[name] {
+			if depth >= maxSchemaDepth {
+				continue
+			}
+			include, err := randomIndex(2)
+			if err != nil {
+				return nil, err
+			}
+			if include == 0 {
+				continue
+			}
+		}
+		value, err := g.generate(properties[name], depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		obj[name] = value
+	}
+	for _, name := range requiredNames {
+		if _, ok := obj[name]; !ok {
+			// Required but undeclared: any string satisfies it.
+			value, err := g.generateString(map[string]interface{}{})
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = value
+		}
+	}
+	return obj, nil
+}
+
+func (g *schemaGenerator) generateArray(s map[string]interface{}, depth int) (interface{}, error) {
+	prefix, _ := s["prefixItems"].([]interface{})
+	var items interface{} = true
+	switch v := s["items"].(type) {
+	case []interface{}:
+		// Draft 4-7 tuple form.
+		prefix = v
+		if extra, ok := s["additionalItems"]; ok {
+			items = extra
+		}
+	case nil:
+	default:
+		items = v
+	}
+
+	minItems, _ := schemaInt(s, "minItems")
+	maxItems, ok := schemaInt(s, "maxItems")
+	if !ok {
+		maxItems = max(minItems, len(prefix)) + 3
+	}
+	if depth >= maxSchemaDepth {
+		maxItems = max(minItems, 0)
+	}
+	if maxItems < minItems {
+		return nil, fmt.Errorf("maxItems is less than minItems")
+	}
+	n, err := randomIndex(maxItems - minItems + 1)
+	if err != nil {
+		return nil, err
+	}
+	length := minItems + n
+	if items == false && length > len(prefix) {
+		length = max(len(prefix), minItems)
+	}
+
+	unique, _ := s["uniqueItems"].(bool)
+	seen := make(map[string]bool)
+	arr := make([]interface{}, 0, length)
+	for attempts := 0; len(arr) < length; attempts++ {
+		if attempts > length*100+100 {
+			return nil, fmt.Errorf("could not generate %d unique items", length)
+		}
+		schema := items
+		if len(arr) < len(prefix) {
+			schema = prefix[len(arr)]
+		}
+		value, err := g.generate(schema, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if unique {
+			key, _ := json.Marshal(value)
+			if seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+		}
+		arr = append(arr, value)
+	}
+	return arr, nil
+}
+
+func (g *schemaGenerator) generateString(s map[string]interface{}) (interface{}, error) {
+	if pattern, ok := s["pattern"].(string); ok {
+		return g.generatePattern(pattern, s)
+	}
+
+	if format, ok := s["format"].(string); ok {
+		if value, ok, err := g.generateFormatLength(format, s); ok || err != nil {
+			return value, err
+		}
+	}
+
+	minLength, hasMin := schemaInt(s, "minLength")
+	maxLength, hasMax := schemaInt(s, "maxLength")
+	if !hasMax {
+		maxLength = minLength + 16
+	}
+	// Prefer non-empty strings unless the schema asks for them.
+	if !hasMin && maxLength > 0 {
+		minLength = 1
+	}
+	if maxLength < minLength {
+		return nil, fmt.Errorf("maxLength is less than minLength")
+	}
+	n, err := randomIndex(maxLength - minLength + 1)
+	if err != nil {
+		return nil, err
+	}
+	if minLength+n == 0 {
+		return "", nil
+	}
+	return generateRandomString(minLength + n)
+}
+
+// generatePattern produces a string matching pattern with minLength to
+// maxLength characters. Unbounded repetitions may stretch to minLength, and
+// candidates of the wrong length are redrawn.
+func (g *schemaGenerator) generatePattern(pattern string, s map[string]interface{}) (string, error) {
+	minLength, _ := schemaInt(s, "minLength")
+	maxLength, hasMax := schemaInt(s, "maxLength")
+	if hasMax && maxLength < minLength {
+		return "", fmt.Errorf("maxLength is less than minLength")
+	}
+
+	maxRepeat := max(defaultMaxRepeat, minLength)
+	key := fmt.Sprintf("%d:%s", maxRepeat, pattern)
+	gen, ok := g.patterns[key]
+	if !ok {
+		var err error
+		if gen, err = newRegexGenerator(pattern, maxRepeat); err != nil {
+			return "", err
+		}
+		g.patterns[key] = gen
+	}
+
+	for attempt := 0; attempt < 1000; attempt++ {
+		value, err := gen.generate()
+		if err != nil {
+			return "", err
+		}
+		n := utf8.RuneCountInString(value)
+		if n >= minLength && (!hasMax || n <= maxLength) {
+			return value, nil
+		}
+	}
+	if hasMax {
+		return "", fmt.Errorf("could not generate a string matching %s with %d to %d characters", pattern, minLength, maxLength)
+	}
+	return "", fmt.Errorf("could not generate a string matching %s with at least %d characters", pattern, minLength)
+}
+
+// generateFormatLength produces a value for format with minLength to
+// maxLength characters, redrawing values of the wrong length. ok is false
+// for formats generateFormat does not know.
+func (g *schemaGenerator) generateFormatLength(format string, s map[string]interface{}) (string, bool, error) {
+	minLength, _ := schemaInt(s, "minLength")
+	maxLength, hasMax := schemaInt(s, "maxLength")
+	if hasMax && maxLength < minLength {
+		return "", true, fmt.Errorf("maxLength is less than minLength")
+	}
+
+	for attempt := 0; attempt < 1000; attempt++ {
+		value, ok, err := g.generateFormat(format)
+		if !ok || err != nil {
+			return value, ok, err
+		}
+		n := utf8.RuneCountInString(value)
+		if n >= minLength && (!hasMax || n <= maxLength) {
+			return value, true, nil
+		}
+	}
+	if hasMax {
+		return "", true, fmt.Errorf("could not generate a %s string with %d to %d characters", format, minLength, maxLength)
+	}
+	return "", true, fmt.Errorf("could not generate a %s string with at least %d characters", format, minLength)
+}
+
+// generateFormat produces a value for the well-known string formats. ok is
+// false for formats it does not know.
+func (g *schemaGenerator) generateFormat(format string) (string, bool, error) {
+	switch format {
+	case "date-time", "date", "time":
+		// Any second between 2000-01-01 and 2030-01-01.
+		secs, err := randomInt(big.NewInt(946771200))
+		if err != nil {
+			return "", true, err
+		}
+		t := time.Unix(946684800+secs.Int64(), 0).UTC()
+		layout := map[string]string{"date-time": time.RFC3339, "date": "2006-01-02", "time": "15:04:05Z"}[format]
+		return t.Format(layout), true, nil
+	case "uuid":
+		value, err := generateUUID()
+		return value, true, err
+	case "email", "idn-email":
+		value, err := g.faker.generate("email")
+		return value, true, err
+	case "uri", "url", "iri":
+		value, err := g.faker.generate("url")
+		return value, true, err
+	case "hostname", "idn-hostname":
+		value, err := g.faker.generate("domain")
+		return value, true, err
+	case "ipv4", "ipv6":
+		value, err := g.faker.generate(format)
+		return value, true, err
+	}
+	return "", false, nil
+}
+
+// generateSchemaNumber draws a multiple of the step (multipleOf, or 1 for
+// integers and 0.01 for numbers) that satisfies the bounds. Numbers without
+// multipleOf whose bounds fall between two steps are sampled continuously
+// instead. Draft 4 boolean exclusiveMinimum/exclusiveMaximum and the later
+// numeric forms are both understood.
+func generateSchemaNumber(s map[string]interface{}, integer bool) (interface{}, error) {
+	step := big.NewRat(1, 100)
+	if integer {
+		step = big.NewRat(1, 1)
+	}
+	m, hasMultiple := schemaRat(s, "multipleOf")
+	if hasMultiple {
+		if m.Sign() <= 0 {
+			return nil, fmt.Errorf("multipleOf must be positive")
+		}
+		step = m
+	}
+
+	lo, loOK := schemaRat(s, "minimum")
+	hi, hiOK := schemaRat(s, "maximum")
+	loExclusive, hiExclusive := s["exclusiveMinimum"] == true, s["exclusiveMaximum"] == true
+	if v, ok := schemaRat(s, "exclusiveMinimum"); ok && (!loOK || v.Cmp(lo) >= 0) {
+		lo, loOK, loExclusive = v, true, true
+	}
+	if v, ok := schemaRat(s, "exclusiveMaximum"); ok && (!hiOK || v.Cmp(hi) <= 0) {
+		hi, hiOK, hiExclusive = v, true, true
+	}
+	switch {
+	case !loOK && !hiOK:
+		lo, hi = big.NewRat(0, 1), big.NewRat(1000, 1)
+	case !loOK:
+		lo = new(big.Rat).Sub(hi, big.NewRat(1000, 1))
+	case !hiOK:
+		hi = new(big.Rat).Add(lo, big.NewRat(1000, 1))
+	}
+
+	// Bounds on the multiplier k, where the value is k * step.
+	kLo := ceilRat(new(big.Rat).Quo(lo, step))
+	if loExclusive && new(big.Rat).Mul(new(big.Rat).SetInt(kLo), step).Cmp(lo) == 0 {
+		kLo.Add(kLo, big.NewInt(1))
+	}
+	kHi := floorRat(new(big.Rat).Quo(hi, step))
+	if hiExclusive && new(big.Rat).Mul(new(big.Rat).SetInt(kHi), step).Cmp(hi) == 0 {
+		kHi.Sub(kHi, big.NewInt(1))
+	}
+	if kHi.Cmp(kLo) < 0 {
+		if !integer && !hasMultiple {
+			return generateContinuousNumber(lo, hi, loExclusive, hiExclusive)
+		}
+		return nil, fmt.Errorf("no %s satisfies the bounds", map[bool]string{true: "integer", false: "number"}[integer])
+	}
+
+	k, err := generateRandomBigInt(kLo, new(big.Int).Add(kHi, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Rat).Mul(new(big.Rat).SetInt(k), step)
+	return ratNumber(value), nil
+}
+
+// generateContinuousNumber draws a number between lo and hi, rounded to
+// enough decimal places to tell ten thousand points of the range apart.
+// Rounded values that leave the bounds are redrawn.
+func generateContinuousNumber(lo, hi *big.Rat, loExclusive, hiExclusive bool) (interface{}, error) {
+	width := new(big.Rat).Sub(hi, lo)
+	switch {
+	case width.Sign() < 0, width.Sign() == 0 && (loExclusive || hiExclusive):
+		return nil, fmt.Errorf("no number satisfies the bounds")
+	case width.Sign() == 0:
+		return ratNumber(lo), nil
+	}
+
+	resolution := new(big.Rat).Quo(width, big.NewRat(10000, 1))
+	places := 0
+	for unit := big.NewRat(1, 1); unit.Cmp(resolution) > 0; places++ {
+		unit.Quo(unit, big.NewRat(10, 1))
+	}
+
+	scale := new(big.Int).Lsh(big.NewInt(1), 53)
+	for attempt := 0; attempt < 1000; attempt++ {
+		k, err := randomInt(new(big.Int).Add(scale, big.NewInt(1)))
+		if err != nil {
+			return nil, err
+		}
+		value := new(big.Rat).Mul(width, new(big.Rat).SetFrac(k, scale))
+		text := value.Add(value, lo).FloatString(places)
+		value, _ = new(big.Rat).SetString(text)
+		if c := value.Cmp(lo); c < 0 || c == 0 && loExclusive {
+			continue
+		}
+		if c := value.Cmp(hi); c > 0 || c == 0 && hiExclusive {
+			continue
+		}
+		if places > 0 {
+			text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+		}
+		return json.Number(text), nil
+	}
+	return nil, fmt.Errorf("no number satisfies the bounds")
+}
+
+// ratNumber renders r as the shortest exact decimal, falling back to 10
+// places for repeating fractions.
+func ratNumber(r *big.Rat) json.Number {
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	for places := 1; places < 10; places++ {
+		s := r.FloatString(places)
+		if back, ok := new(big.Rat).SetString(s); ok && back.Cmp(r) == 0 {
+			return json.Number(s)
+		}
+	}
+	return json.Number(r.FloatString(10))
+}
+
+// floorRat returns the largest integer not greater than r.
+func floorRat(r *big.Rat) *big.Int {
+	q, _ := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+	return q
+}
+
+func schemaRat(s map[string]interface{}, key string) (*big.Rat, bool) {
+	n, ok := s[key].(json.Number)
+	if !ok {
+		return nil, false
+	}
+	r, err := parseDecimal(n.String())
+	return r, err == nil
+}
+
+func schemaInt(s map[string]interface{}, key string) (int, bool) {
+	n, ok := s[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(n.String())
+	return i, err == nil
+}
+
+func copySchema(s map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(s))
+	for k, v := range s {
+		out[k] = v
+	}
+	return out
+}
+
+// mergeSchema folds part into dst: properties are merged, required lists
+// are combined, and other keywords from part take precedence.
+func mergeSchema(dst, part map[string]interface{}) {
+	for k, v := range part {
+		switch k {
+		case "properties":
+			props := make(map[string]interface{})
+			if existing, ok := dst[k].(map[string]interface{}); ok {
+				for name, schema := range existing {
+					props[name] = schema
+				}
+			}
+			if added, ok := v.(map[string]interface{}); ok {
+				for name, schema := range added {
+					props[name] = schema
+				}
+			}
+			dst[k] = props
+		case "required":
+			existing, _ := dst[k].([]interface{})
+			added, _ := v.([]interface{})
+			dst[k] = append(append([]interface{}{}, existing...), added...)
+		default:
+			dst[k] = v
+		}
+	}
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
+	"math/big"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -232,3 +235,438 @@ func TestJSONValidateFromFile(t *testing.T) {
 		t.Errorf("Expected output %q, got %q", expected, output)
 	}
 }
+
+func TestJSONBinaryFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		flags    map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "json to msgpack",
+			args:     []string{`{"a":1,"b":1.0,"c":[-5,"x",null,true]}`},
+			flags:    map[string]string{"to": "msgpack"},
+			expected: "Converted JSON to MessagePack (base64):\ng6FhAaFiyz/wAAAAAAAAoWOU+6F4wMM=",
+		},
+		{
+			name:     "msgpack to json keeps float type",
+			args:     []string{"g6FhAaFiyz/wAAAAAAAAoWOU+6F4wMM="},
+			flags:    map[string]string{"from": "msgpack", "minify": "true"},
+			expected: "Minified JSON from MessagePack:\n{\"a\":1,\"b\":1.0,\"c\":[-5,\"x\",null,true]}",
+		},
+		{
+			name:     "json to cbor",
+			args:     []string{`{"a":1,"b":1.5,"n":-300}`},
+			flags:    map[string]string{"to": "cbor"},
+			expected: "Converted JSON to CBOR (base64):\no2FhAWFi+z/4AAAAAAAAYW45ASs=",
+		},
+		{
+			name:     "cbor to json",
+			args:     []string{"o2FhAWFi+z/4AAAAAAAAYW45ASs="},
+			flags:    map[string]string{"from": "cbor", "minify": "true"},
+			expected: "Minified JSON from CBOR:\n{\"a\":1,\"b\":1.5,\"n\":-300}",
+		},
+		{
+			name:     "cbor indefinite map and half float",
+			args:     []string{"v2JhYvk8AP8="},
+			flags:    map[string]string{"from": "cbor", "minify": "true"},
+			expected: "Minified JSON from CBOR:\n{\"ab\":1.0}",
+		},
+		{
+			name:     "integers wider than 64 bits are CBOR bignums",
+			args:     []string{`[18446744073709551616,-18446744073709551617]`},
+			flags:    map[string]string{"to": "cbor"},
+			expected: "Converted JSON to CBOR (base64):\ngsJJAQAAAAAAAAAAw0kBAAAAAAAAAAA=",
+		},
+		{
+			name:    "integers wider than 64 bits in msgpack",
+			args:    []string{`18446744073709551616`},
+			flags:   map[string]string{"to": "msgpack"},
+			wantErr: true,
+		},
+		{
+			name:     "validate cbor",
+			args:     []string{"9Q=="},
+			flags:    map[string]string{"from": "cbor", "validate": "true"},
+			expected: "✓ Valid CBOR",
+		},
+		{
+			name:    "truncated msgpack",
+			args:    []string{"kw=="},
+			flags:   map[string]string{"from": "msgpack"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			args:    []string{"{}"},
+			flags:   map[string]string{"to": "yaml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags to default values
+			jsonMinify = false
+			jsonValidate = false
+			jsonFromFile = false
+			jsonFrom = "json"
+			jsonTo = "json"
+			jsonOutputFile = ""
+			defer func() { jsonFrom, jsonTo = "json", "json" }()
+
+			cmd := &cobra.Command{
+				Use:  "json [json-string|file]",
+				Args: cobra.ExactArgs(1),
+				RunE: runJSON,
+			}
+
+			cmd.Flags().BoolVarP(&jsonMinify, "minify", "m", false, "Minify JSON instead of pretty printing")
+			cmd.Flags().BoolVarP(&jsonValidate, "validate", "v", false, "Only validate JSON, don't output")
+			cmd.Flags().BoolVarP(&jsonFromFile, "file", "f", false, "Read JSON from file instead of string")
+			cmd.Flags().StringVar(&jsonFrom, "from", "json", "Input format: json, msgpack, cbor")
+			cmd.Flags().StringVar(&jsonTo, "to", "json", "Output format: json, msgpack, cbor")
+
+			for flag, value := range tt.flags {
+				cmd.Flags().Set(flag, value)
+			}
+
+			old := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := cmd.RunE(cmd, tt.args)
+
+			w.Close()
+			os.Stdout = old
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := strings.TrimSpace(buf.String())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if output != tt.expected {
+					t.Errorf("Expected output %q, got %q", tt.expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestBinaryJSONRoundTrip(t *testing.T) {
+	input := `{"big":18446744073709551615,"f":-0.25,"list":[1,[2,{"x":"y"}]],"min":-9223372036854775808,"s":"` + strings.Repeat("z", 300) + `"}`
+	data, err := parseJSON([]byte(input), true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	codecs := map[string]struct {
+		encode func(interface{}) ([]byte, error)
+		decode func([]byte) (interface{}, error)
+	}{
+		"msgpack": {encodeMsgpack, decodeMsgpack},
+		"cbor":    {encodeCBOR, decodeCBOR},
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := codec.encode(data)
+			if err != nil {
+				t.Fatalf("Unexpected encode error: %v", err)
+			}
+			decoded, err := codec.decode(encoded)
+			if err != nil {
+				t.Fatalf("Unexpected decode error: %v", err)
+			}
+			out, _ := json.Marshal(decoded)
+			if string(out) != input {
+				t.Errorf("Round trip mismatch:\n got %s\nwant %s", out, input)
+			}
+		})
+	}
+}
+
+func TestCBORBignumRoundTrip(t *testing.T) {
+	input := `[18446744073709551616,-18446744073709551617,123456789012345678901234567890]`
+	data, err := parseJSON([]byte(input), true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	encoded, err := encodeCBOR(data)
+	if err != nil {
+		t.Fatalf("Unexpected encode error: %v", err)
+	}
+	decoded, err := decodeCBOR(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	if out, _ := json.Marshal(decoded); string(out) != input {
+		t.Errorf("Round trip mismatch:\n got %s\nwant %s", out, input)
+	}
+}
+
+func runJSONGenerateTest(t *testing.T, flags map[string]string) (string, error) {
+	t.Helper()
+	jsonSchemaFile = ""
+	jsonGenerateCount = 1
+	jsonGenerateSeed = ""
+	jsonGenerateOutput = "json"
+
+	cmd := &cobra.Command{
+		Use:  "generate",
+		RunE: runJSONGenerate,
+	}
+	cmd.Flags().StringVarP(&jsonSchemaFile, "schema", "s", "", "JSON Schema file")
+	cmd.Flags().IntVarP(&jsonGenerateCount, "count", "n", 1, "Number of documents to generate")
+	cmd.Flags().StringVar(&jsonGenerateSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
+	cmd.Flags().StringVarP(&jsonGenerateOutput, "output", "o", "json", "Output format: json, ndjson")
+	for flag, value := range flags {
+		cmd.Flags().Set(flag, value)
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := cmd.RunE(cmd, nil)
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return strings.TrimSpace(buf.String()), err
+}
+
+func TestJSONGenerate(t *testing.T) {
+	output, err := runJSONGenerateTest(t, map[string]string{"schema": "testdata/schema.json", "count": "50", "output": "ndjson"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	zip := regexp.MustCompile(`^\d{5}$`)
+	sku := regexp.MustCompile(`^[A-Z]{3}-\d{4}$`)
+	declared := map[string]bool{"id": true, "email": true, "age": true, "score": true, "sku": true, "status": true,
+		"tags": true, "address": true, "created": true, "manager": true, "nickname": true}
+
+	var check func(doc map[string]interface{})
+	check = func(doc map[string]interface{}) {
+		for key := range doc {
+			if !declared[key] {
+				t.Errorf("Unexpected property %q", key)
+			}
+		}
+		for _, key := range []string{"id", "email", "age", "tags", "address", "status"} {
+			if _, ok := doc[key]; !ok {
+				t.Errorf("Missing required property %q in %v", key, doc)
+			}
+		}
+		if age, ok := doc["age"].(float64); !ok || age < 18 || age >= 90 || age != float64(int(age)) {
+			t.Errorf("Invalid age %v", doc["age"])
+		}
+		if score, ok := doc["score"].(float64); ok && (score < 0 || score > 1 || int(score*100+0.5)%5 != 0) {
+			t.Errorf("Invalid score %v", score)
+		}
+		if s, ok := doc["sku"]; ok && !sku.MatchString(s.(string)) {
+			t.Errorf("Invalid sku %v", s)
+		}
+		if s := doc["status"]; s != "active" && s != "suspended" && s != "deleted" {
+			t.Errorf("Invalid status %v", s)
+		}
+		tags, _ := doc["tags"].([]interface{})
+		seen := map[interface{}]bool{}
+		for _, tag := range tags {
+			if n := len(tag.(string)); n < 3 || n > 8 || seen[tag] {
+				t.Errorf("Invalid or duplicate tag %v", tag)
+			}
+			seen[tag] = true
+		}
+		if len(tags) < 1 || len(tags) > 4 {
+			t.Errorf("Expected 1-4 tags, got %d", len(tags))
+		}
+		address, _ := doc["address"].(map[string]interface{})
+		if z, _ := address["zip"].(string); !zip.MatchString(z) {
+			t.Errorf("Invalid zip %v", address["zip"])
+		}
+		if id, _ := doc["id"].(string); len(id) != 36 {
+			t.Errorf("Invalid uuid %v", doc["id"])
+		}
+		if manager, ok := doc["manager"].(map[string]interface{}); ok {
+			check(manager)
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &doc); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+		check(doc)
+	}
+}
+
+func TestJSONGenerateSeeded(t *testing.T) {
+	flags := map[string]string{"schema": "testdata/schema.json", "count": "3", "seed": "fixtures"}
+	first, err := runJSONGenerateTest(t, flags)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := runJSONGenerateTest(t, flags)
+	if first != second {
+		t.Error("Expected identical documents for the same seed")
+	}
+	var docs []interface{}
+	if err := json.Unmarshal([]byte(first), &docs); err != nil || len(docs) != 3 {
+		t.Errorf("Expected a JSON array of 3 documents, got %q", first)
+	}
+}
+
+func TestSchemaGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		valid   func(interface{}) bool
+		wantErr bool
+	}{
+		{
+			name:   "draft 4 exclusive bounds",
+			schema: `{"type": "integer", "minimum": 1, "maximum": 3, "exclusiveMinimum": true, "exclusiveMaximum": true}`,
+			valid:  func(v interface{}) bool { return v == json.Number("2") },
+		},
+		{
+			name:   "multipleOf with big bounds",
+			schema: `{"type": "integer", "minimum": 1e20, "maximum": 1e20, "multipleOf": 4}`,
+			valid:  func(v interface{}) bool { return v == json.Number("100000000000000000000") },
+		},
+		{
+			name:   "const",
+			schema: `{"const": {"a": [1]}}`,
+			valid:  func(v interface{}) bool { return schemaString(v) == `{"a":[1]}` },
+		},
+		{
+			name:   "allOf merges required properties",
+			schema: `{"allOf": [{"properties": {"a": {"const": 1}}, "required": ["a"]}, {"properties": {"b": {"const": 2}}, "required": ["b"]}]}`,
+			valid:  func(v interface{}) bool { return schemaString(v) == `{"a":1,"b":2}` },
+		},
+		{
+			name:   "tuple items",
+			schema: `{"type": "array", "prefixItems": [{"const": "x"}, {"type": "boolean"}], "items": false, "minItems": 2}`,
+			valid: func(v interface{}) bool {
+				arr, ok := v.([]interface{})
+				return ok && len(arr) == 2 && arr[0] == "x"
+			},
+		},
+		{
+			name:   "pattern within length bounds",
+			schema: `{"type": "string", "pattern": "^[a-z]+$", "minLength": 5, "maxLength": 8}`,
+			valid: func(v interface{}) bool {
+				s, ok := v.(string)
+				return ok && len(s) >= 5 && len(s) <= 8
+			},
+		},
+		{
+			name:   "pattern stretched to minLength",
+			schema: `{"type": "string", "pattern": "^[a-z]+$", "minLength": 30}`,
+			valid: func(v interface{}) bool {
+				s, ok := v.(string)
+				return ok && len(s) >= 30
+			},
+		},
+		{
+			name:    "pattern too short for minLength",
+			schema:  `{"type": "string", "pattern": "^[0-9]{3}$", "minLength": 5}`,
+			wantErr: true,
+		},
+		{
+			name:   "number bounds between hundredths",
+			schema: `{"type": "number", "minimum": 0.501, "maximum": 0.509}`,
+			valid: func(v interface{}) bool {
+				r, ok := new(big.Rat).SetString(string(v.(json.Number)))
+				return ok && r.Cmp(big.NewRat(501, 1000)) >= 0 && r.Cmp(big.NewRat(509, 1000)) <= 0
+			},
+		},
+		{
+			name:   "tiny exclusive number range",
+			schema: `{"type": "number", "exclusiveMinimum": 1e-15, "exclusiveMaximum": 2e-15}`,
+			valid: func(v interface{}) bool {
+				r, ok := new(big.Rat).SetString(string(v.(json.Number)))
+				return ok && r.Cmp(big.NewRat(1, 1e15)) > 0 && r.Cmp(big.NewRat(2, 1e15)) < 0
+			},
+		},
+		{
+			name:    "impossible number",
+			schema:  `{"type": "number", "exclusiveMinimum": 0.505, "maximum": 0.505}`,
+			wantErr: true,
+		},
+		{
+			name:   "format within length bounds",
+			schema: `{"type": "string", "format": "email", "maxLength": 22}`,
+			valid: func(v interface{}) bool {
+				s, ok := v.(string)
+				return ok && strings.Contains(s, "@") && len(s) <= 22
+			},
+		},
+		{
+			name:    "format longer than maxLength",
+			schema:  `{"type": "string", "format": "uuid", "maxLength": 10}`,
+			wantErr: true,
+		},
+		{
+			name:    "impossible integer",
+			schema:  `{"type": "integer", "minimum": 1.2, "maximum": 1.8}`,
+			wantErr: true,
+		},
+		{
+			name:    "unresolvable ref",
+			schema:  `{"$ref": "#/$defs/missing"}`,
+			wantErr: true,
+		},
+		{
+			name:    "remote ref",
+			schema:  `{"$ref": "https://example.com/schema.json"}`,
+			wantErr: true,
+		},
+		{
+			name:    "required infinite recursion",
+			schema:  `{"type": "object", "required": ["self"], "properties": {"self": {"$ref": "#"}}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := parseJSON([]byte(tt.schema), true)
+			if err != nil {
+				t.Fatalf("Invalid test schema: %v", err)
+			}
+			gen, err := newSchemaGenerator(schema)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			value, err := gen.generate(schema, 0)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tt.valid(value) {
+				t.Errorf("Invalid instance %s", schemaString(value))
+			}
+		})
+	}
+}
+
+func schemaString(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
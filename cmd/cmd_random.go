@@ -1,10 +1,14 @@
 package cmd
 
 import (
-	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -12,81 +16,517 @@ import (
 var randomCmd = &cobra.Command{
 	Use:   "random",
 	Short: "Generate random values",
-	Long:  `Generate random strings, numbers, or UUIDs.`,
-	RunE:  runRandom,
+	Long: `Generate random strings, passphrases, numbers, or UUIDs.
+
+Strings are drawn from a named --alphabet or a custom --charset, optionally
+without ambiguous characters, and can be required to contain at least one
+character from given classes (lower, upper, digit, symbol).
+
+Passphrases are diceware-style: words drawn from the embedded EFF large
+wordlist (or a --wordlist file), joined by a separator.
+
+UUIDs default to version 4; --uuid-version selects any RFC 9562 version,
+with --namespace and --name for the name-based versions 3 and 5.
+
+ULID, KSUID, NanoID, Snowflake, and MongoDB ObjectID generators are also
+available. NanoIDs honour --charset and --length (default 21); Snowflakes use
+--epoch and --worker. Use "plz random decode" to read the timestamp back.
+
+Numbers are integers in [--min, --max) of any size; scientific notation such
+as -9e18 is accepted. Floats are decimals in the same range with --precision
+places. Both can instead follow a normal (--mean, --stddev), exponential
+(--mean), or, for numbers, poisson (--mean) --distribution; samples outside
+an explicitly given --min or --max are redrawn. --type weighted picks among
+--weights value:weight pairs in proportion to their weights.
+
+Regex strings match --pattern (Go RE2 syntax); unbounded repetition such as
+* or {2,} adds at most --max-repeat extra copies.
+
+Dice are rolled from standard notation given as the argument, e.g.
+  plz random --type dice 4d6kh3+2
+NdS rolls N S-sided dice (d% is d100); ! explodes dice on their highest face;
+kN/khN and klN keep the highest or lowest N, dN/dlN and dhN drop them (each
+explosion roll counts as a die of its own, so 4d6!kh3 keeps three); terms
+and modifiers are joined with + and -. A single roll also shows the dice,
+with dropped ones in parentheses. --odds prints the exact probability of
+every total instead.
+
+IP addresses are drawn uniformly from the hosts of one or more --cidr
+networks, skipping IPv4 network and broadcast addresses and the IPv6
+Subnet-Router anycast address (except in /31, /127, and single-address
+networks). MAC addresses are locally administered unicast addresses. Ports
+come from --ports ranges (default 1024-65535).
+
+Lorem ipsum placeholder text is --paragraphs (default 3), --sentences, or
+--lorem-words long. The classic --lorem-mode opens with "Lorem ipsum dolor
+sit amet" and uses the Latin vocabulary; random draws words from the
+passphrase wordlist (or --wordlist) instead. --markup html wraps paragraphs
+in <p> tags, and markdown wraps lines at 80 columns. Text output separates
+values with blank lines.
+
+Bytes are --length (default 32) random bytes printed in an --encoding shared
+with "plz encode"; raw writes the binary bytes to stdout. "plz random
+keypair" generates Ed25519, ECDSA P-256, and RSA keys.
+
+--template renders whole JSON records instead of single values, e.g.
+  '{"id":"{{uuid}}","age":{{int 18 90}},"email":"{{email}}"}'
+Placeholders use Go template syntax. Generators: seq, uuid [version],
+int MIN MAX (inclusive), float MIN MAX [precision], bool, choice A B ...,
+string [length], passphrase [words], bytes [n] (hex), regex PATTERN, ulid,
+ksuid, nanoid, objectid, and every "plz fake" kind in camelCase (name, firstName, email,
+userAgent, ...) with an optional locale argument. Strings are JSON-escaped;
+each record must be valid JSON and is printed as NDJSON, or as an array
+with --output json.
+
+Any type can be generated in bulk with --count, printed as lines, a JSON
+array, or CSV. --unique guarantees no duplicates within the batch and fails
+when the type cannot produce enough distinct values.
+
+--seed replaces the system CSPRNG with a deterministic ChaCha8 stream so test
+fixtures are reproducible. Seeded output is NOT cryptographically secure,
+and time-based IDs still embed the current time.`,
+	RunE: runRandom,
 }
 
 var (
-	randomType   string
-	randomLength int
-	randomMin    int64
-	randomMax    int64
+	randomType             string
+	randomLength           int
+	randomMin              string
+	randomMax              string
+	randomDistribution     string
+	randomMean             float64
+	randomStddev           float64
+	randomPrecision        int
+	randomWeights          []string
+	randomEncoding         string
+	randomTemplate         string
+	randomPattern          string
+	randomMaxRepeat        int
+	randomAlphabet         string
+	randomCharset          string
+	randomExcludeAmbiguous bool
+	randomRequire          []string
+	randomWords            int
+	randomSeparator        string
+	randomCapitalize       string
+	randomDigits           int
+	randomWordlist         string
+	randomUUIDVersion      int
+	randomNamespace        string
+	randomName             string
+	randomEpoch            int64
+	randomWorker           int64
+	randomCount            int
+	randomOutput           string
+	randomUnique           bool
+	randomSeed             string
+	randomOdds             bool
+	randomCIDRs            []string
+	randomPorts            []string
+	randomLoremWords       int
+	randomSentences        int
+	randomParagraphs       int
+	randomLoremMode        string
+	randomMarkup           string
 )
 
 func init() {
-	randomCmd.Flags().StringVarP(&randomType, "type", "t", "string", "Type: string, number, uuid")
-	randomCmd.Flags().IntVarP(&randomLength, "length", "l", 16, "Length for string generation")
-	randomCmd.Flags().Int64Var(&randomMin, "min", 0, "Minimum value for number generation")
-	randomCmd.Flags().Int64Var(&randomMax, "max", 100, "Maximum value for number generation")
+	randomCmd.Flags().StringVarP(&randomType, "type", "t", "string", "Type: string, passphrase, number, float, weighted, bytes, regex, dice, ip, mac, port, lorem, uuid, ulid, ksuid, nanoid, snowflake, objectid")
+	randomCmd.Flags().IntVarP(&randomLength, "length", "l", 16, "Length for string generation (bytes default to 32)")
+	randomCmd.Flags().StringVar(&randomMin, "min", "0", "Minimum value (inclusive) for numbers and floats")
+	randomCmd.Flags().StringVar(&randomMax, "max", "100", "Maximum value (exclusive) for numbers and floats")
+	randomCmd.Flags().StringVar(&randomDistribution, "distribution", "uniform", "Distribution for numbers and floats: uniform, normal, exponential, poisson")
+	randomCmd.Flags().Float64Var(&randomMean, "mean", 0, "Mean for normal, exponential, and poisson distributions")
+	randomCmd.Flags().Float64Var(&randomStddev, "stddev", 1, "Standard deviation for the normal distribution")
+	randomCmd.Flags().IntVar(&randomPrecision, "precision", 2, "Decimal places for floats")
+	randomCmd.Flags().StringSliceVar(&randomWeights, "weights", nil, "Choices for --type weighted as value:weight pairs")
+	randomCmd.Flags().StringVarP(&randomEncoding, "encoding", "e", "hex", "Encoding for bytes: hex, base64, base64url, raw")
+	randomCmd.Flags().StringVar(&randomPattern, "pattern", "", "Regular expression for --type regex")
+	randomCmd.Flags().IntVar(&randomMaxRepeat, "max-repeat", defaultMaxRepeat, "Extra repetitions allowed for *, +, and {n,} in --pattern")
+	randomCmd.Flags().BoolVar(&randomOdds, "odds", false, "Print the exact distribution of a --type dice expression instead of rolling")
+	randomCmd.Flags().StringSliceVar(&randomCIDRs, "cidr", nil, "Networks for --type ip, e.g. 10.0.0.0/8,2001:db8::/32")
+	randomCmd.Flags().StringSliceVar(&randomPorts, "ports", []string{"1024-65535"}, "Ports and ranges for --type port, e.g. 80,8000-8999")
+	randomCmd.Flags().IntVar(&randomLoremWords, "lorem-words", 0, "Number of words for --type lorem")
+	randomCmd.Flags().IntVar(&randomSentences, "sentences", 0, "Number of sentences for --type lorem")
+	randomCmd.Flags().IntVar(&randomParagraphs, "paragraphs", 3, "Number of paragraphs for --type lorem")
+	randomCmd.Flags().StringVar(&randomLoremMode, "lorem-mode", "classic", "Words for --type lorem: classic, random")
+	randomCmd.Flags().StringVar(&randomMarkup, "markup", "text", "Markup for --type lorem: text, html, markdown")
+	randomCmd.Flags().StringVar(&randomTemplate, "template", "", "JSON record template with {{generator}} placeholders, or @file (overrides --type)")
+	randomCmd.Flags().StringVarP(&randomAlphabet, "alphabet", "a", "alphanumeric", "Alphabet for strings: alphanumeric, alpha, lower, upper, numeric, hex, base32, password")
+	randomCmd.Flags().StringVar(&randomCharset, "charset", "", "Custom characters for strings (overrides --alphabet)")
+	randomCmd.Flags().BoolVar(&randomExcludeAmbiguous, "exclude-ambiguous", false, "Exclude look-alike characters (0, O, 1, l, I, |)")
+	randomCmd.Flags().StringSliceVar(&randomRequire, "require", nil, "Require at least one character of each class: lower, upper, digit, symbol")
+	randomCmd.Flags().IntVarP(&randomWords, "words", "w", 6, "Number of words for passphrase generation")
+	randomCmd.Flags().StringVar(&randomSeparator, "separator", "-", "Separator between passphrase words")
+	randomCmd.Flags().StringVar(&randomCapitalize, "capitalize", "none", "Passphrase capitalization: none, title, upper, random")
+	randomCmd.Flags().IntVar(&randomDigits, "digits", 0, "Number of random digits to insert into the passphrase")
+	randomCmd.Flags().StringVar(&randomWordlist, "wordlist", "", "Wordlist file for passphrases (default: embedded EFF large wordlist)")
+	randomCmd.Flags().IntVar(&randomUUIDVersion, "uuid-version", 4, "UUID version: 1, 3, 4, 5, 6, 7")
+	randomCmd.Flags().StringVar(&randomNamespace, "namespace", "", "Namespace for UUID v3/v5: dns, url, oid, x500, or a UUID")
+	randomCmd.Flags().StringVar(&randomName, "name", "", "Name for UUID v3/v5")
+	randomCmd.Flags().Int64Var(&randomEpoch, "epoch", twitterEpoch, "Snowflake epoch in Unix milliseconds")
+	randomCmd.Flags().Int64Var(&randomWorker, "worker", 0, "Snowflake worker ID (0-1023)")
+	randomCmd.Flags().IntVarP(&randomCount, "count", "n", 1, "Number of values to generate")
+	randomCmd.Flags().StringVarP(&randomOutput, "output", "o", "text", "Output format: text, lines, ndjson, json, csv")
+	randomCmd.Flags().BoolVarP(&randomUnique, "unique", "u", false, "Guarantee no duplicates within the batch")
+	randomCmd.Flags().StringVar(&randomSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
 	rootCmd.AddCommand(randomCmd)
 }
 
 func runRandom(cmd *cobra.Command, args []string) error {
+	if randomCount < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+
+	if randomSeed != "" {
+		useSeededSource(randomSeed)
+		defer useCryptoSource()
+	}
+
+	if randomOdds {
+		if strings.ToLower(randomType) != "dice" {
+			return fmt.Errorf("--odds requires --type dice")
+		}
+		d, err := parseDice(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		dist, truncated, err := d.distribution()
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatDiceDistribution(d, dist, truncated))
+		return nil
+	}
+
+	gen, err := newRandomGenerator(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	values, err := collectRandomValues(gen, randomCount, randomUnique)
+	if err != nil {
+		return err
+	}
+	return writeRandomValues(gen, values, randomOutput)
+}
+
+// randomGenerator produces values of a single random type.
+type randomGenerator struct {
+	// name is used in error messages, label prefixes a single value in
+	// text output, and notes are printed after it.
+	name  string
+	label string
+	notes []string
+	// column is the CSV header; it defaults to the --type value.
+	column string
+	// space is the number of distinct values the generator can produce, or
+	// nil when it is too large to matter for uniqueness.
+	space *big.Int
+	// numeric values are emitted as JSON numbers rather than strings.
+	numeric bool
+	// raw values are binary and written to stdout unlabelled and unseparated.
+	raw bool
+	// record values are JSON documents, printed one per line (NDJSON) or
+	// embedded as-is in a JSON array.
+	record bool
+	// block values span several lines; text output prints them unlabelled
+	// and separated by blank lines.
+	block bool
+	next  func() (string, error)
+}
+
+// newRandomGenerator builds the generator selected by --type and its flags.
+func newRandomGenerator(cmd *cobra.Command, args []string) (randomGenerator, error) {
+	if randomTemplate != "" {
+		return newTemplateGenerator(randomTemplate)
+	}
+
 	switch strings.ToLower(randomType) {
 	case "string":
-		result, err := generateRandomString(randomLength)
+		if randomLength <= 0 {
+			return randomGenerator{}, fmt.Errorf("length must be positive")
+		}
+		policy, err := newStringPolicy(randomAlphabet, randomCharset, randomExcludeAmbiguous, randomRequire)
+		if err != nil {
+			return randomGenerator{}, err
+		}
+		return randomGenerator{
+			name:  "random string",
+			label: fmt.Sprintf("Random string (%d chars)", randomLength),
+			notes: []string{fmt.Sprintf("Entropy: %.1f bits (%d-character alphabet)", policy.entropy(randomLength), len(policy.charset))},
+			space: policy.combinations(randomLength),
+			next:  func() (string, error) { return policy.generate(randomLength) },
+		}, nil
+	case "passphrase":
+		words, err := loadWordlist(randomWordlist)
 		if err != nil {
-			return fmt.Errorf("failed to generate random string: %w", err)
+			return randomGenerator{}, err
 		}
-		fmt.Printf("Random string (%d chars): %s\n", randomLength, result)
+		opts := passphraseOptions{
+			words:      randomWords,
+			separator:  randomSeparator,
+			capitalize: randomCapitalize,
+			digits:     randomDigits,
+		}
+		return randomGenerator{
+			name:  "passphrase",
+			label: fmt.Sprintf("Random passphrase (%d words)", randomWords),
+			notes: []string{fmt.Sprintf("Entropy: %.1f bits (%d-word list)", passphraseEntropy(len(words), opts), len(words))},
+			// Word choices alone; capitalization and digits only add to it.
+			space: new(big.Int).Exp(big.NewInt(int64(len(words))), big.NewInt(int64(max(randomWords, 0))), nil),
+			next:  func() (string, error) { return generatePassphrase(words, opts) },
+		}, nil
 	case "number":
-		if randomMax <= randomMin {
-			return fmt.Errorf("max value must be greater than min value")
+		return newNumberGenerator(cmd, false)
+	case "float":
+		return newNumberGenerator(cmd, true)
+	case "weighted":
+		return newWeightedGenerator(randomWeights)
+	case "bytes":
+		size := 32
+		if cmd.Flags().Changed("length") {
+			size = randomLength
+		}
+		if size <= 0 {
+			return randomGenerator{}, fmt.Errorf("length must be positive")
+		}
+		encoding := strings.ToLower(randomEncoding)
+		if _, err := encodeBytes(nil, encoding); err != nil {
+			return randomGenerator{}, err
 		}
-		result, err := generateRandomNumber(randomMin, randomMax)
+		return randomGenerator{
+			name:  "random bytes",
+			label: fmt.Sprintf("Random bytes (%d, %s)", size, encoding),
+			notes: []string{fmt.Sprintf("Entropy: %d bits", size*8)},
+			space: new(big.Int).Lsh(big.NewInt(1), uint(size*8)),
+			raw:   encoding == "raw",
+			next: func() (string, error) {
+				b := make([]byte, size)
+				if err := randomBytes(b); err != nil {
+					return "", err
+				}
+				return encodeBytes(b, encoding)
+			},
+		}, nil
+	case "regex":
+		if randomPattern == "" {
+			return randomGenerator{}, fmt.Errorf("regex type requires --pattern")
+		}
+		gen, err := newRegexGenerator(randomPattern, randomMaxRepeat)
 		if err != nil {
-			return fmt.Errorf("failed to generate random number: %w", err)
+			return randomGenerator{}, err
 		}
-		fmt.Printf("Random number (%d-%d): %d\n", randomMin, randomMax, result)
-	case "uuid":
-		result, err := generateUUID()
+		return randomGenerator{
+			name:  "regex match",
+			label: fmt.Sprintf("Random match for %s", randomPattern),
+			next:  gen.generate,
+		}, nil
+	case "lorem":
+		opts, err := loremOptionsFromFlags(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to generate UUID: %w", err)
+			return randomGenerator{}, err
+		}
+		return newLoremGenerator(opts)
+	case "dice":
+		if len(args) == 0 {
+			return randomGenerator{}, fmt.Errorf("dice type requires an expression such as 4d6kh3+2")
+		}
+		return newDiceGenerator(strings.Join(args, " "))
+	case "ip":
+		return newIPGenerator(randomCIDRs)
+	case "mac":
+		return newMACGenerator(), nil
+	case "port":
+		return newPortGenerator(randomPorts)
+	case "uuid":
+		gen := randomGenerator{
+			name:  "UUID",
+			label: fmt.Sprintf("UUID v%d", randomUUIDVersion),
+			next: func() (string, error) {
+				return generateUUIDVersion(randomUUIDVersion, randomNamespace, randomName)
+			},
+		}
+		switch randomUUIDVersion {
+		case 3, 5:
+			// Name-based UUIDs are deterministic.
+			gen.space = big.NewInt(1)
+		case 4:
+			gen.label = "Random UUID"
+			gen.space = new(big.Int).Lsh(big.NewInt(1), 122)
+		}
+		return gen, nil
+	case "ulid":
+		return randomGenerator{
+			name:  "ULID",
+			label: "Random ULID",
+			next:  func() (string, error) { return generateULID(time.Now()) },
+		}, nil
+	case "ksuid":
+		return randomGenerator{
+			name:  "KSUID",
+			label: "Random KSUID",
+			next:  func() (string, error) { return generateKSUID(time.Now()) },
+		}, nil
+	case "nanoid":
+		alphabet, size := []rune(nanoidAlphabet), nanoidSize
+		if randomCharset != "" {
+			policy, err := newStringPolicy("", randomCharset, false, nil)
+			if err != nil {
+				return randomGenerator{}, err
+			}
+			alphabet = policy.charset
 		}
-		fmt.Printf("Random UUID: %s\n", result)
+		if cmd.Flags().Changed("length") {
+			size = randomLength
+		}
+		return randomGenerator{
+			name:  "NanoID",
+			label: "Random NanoID",
+			space: new(big.Int).Exp(big.NewInt(int64(len(alphabet))), big.NewInt(int64(max(size, 0))), nil),
+			next:  func() (string, error) { return generateNanoID(alphabet, size) },
+		}, nil
+	case "snowflake":
+		return randomGenerator{
+			name:  "Snowflake",
+			label: "Random Snowflake",
+			next:  func() (string, error) { return generateSnowflake(time.Now(), randomEpoch, randomWorker) },
+		}, nil
+	case "objectid":
+		return randomGenerator{
+			name:  "ObjectID",
+			label: "Random ObjectID",
+			next:  func() (string, error) { return generateObjectID(time.Now()) },
+		}, nil
 	default:
-		return fmt.Errorf("unsupported random type: %s (supported: string, number, uuid)", randomType)
+		return randomGenerator{}, fmt.Errorf("unsupported random type: %s (supported: string, passphrase, number, float, weighted, bytes, regex, dice, ip, mac, port, lorem, uuid, ulid, ksuid, nanoid, snowflake, objectid)", randomType)
 	}
+}
 
-	return nil
+// collectRandomValues draws count values. With unique set, duplicates are
+// redrawn, and a space smaller than count is rejected up front.
+func collectRandomValues(gen randomGenerator, count int, unique bool) ([]string, error) {
+	if unique && gen.space != nil && gen.space.Cmp(big.NewInt(int64(count))) < 0 {
+		return nil, fmt.Errorf("cannot generate %d unique values: only %s possible", count, gen.space)
+	}
+
+	values := make([]string, 0, count)
+	seen := make(map[string]bool)
+	// Bound the redraws so a near-exhausted space fails instead of spinning.
+	attempts := 0
+	maxAttempts := count*100 + 1000
+	for len(values) < count {
+		value, err := gen.next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", gen.name, err)
+		}
+		if unique {
+			attempts++
+			if seen[value] {
+				if attempts > maxAttempts {
+					return nil, fmt.Errorf("could not find %d unique values after %d attempts", count, attempts)
+				}
+				continue
+			}
+			seen[value] = true
+		}
+		values = append(values, value)
+	}
+	return values, nil
 }
 
-func generateRandomString(length int) (string, error) {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+// writeRandomValues prints values in the requested format. Text output keeps
+// the labelled form for a single value and prints one value per line
+// otherwise.
+func writeRandomValues(gen randomGenerator, values []string, format string) error {
+	// Keep machine-readable output clean but still flag seeded values.
+	if randomSeeded && (len(values) > 1 || strings.ToLower(format) != "text" || gen.raw || gen.record || gen.block) {
+		fmt.Fprintln(os.Stderr, seedWarning)
+	}
+
+	if gen.raw {
+		if strings.ToLower(format) != "text" {
+			return fmt.Errorf("raw bytes cannot be written as %s; use --encoding hex, base64, or base64url", format)
+		}
+		_, err := io.WriteString(os.Stdout, strings.Join(values, ""))
+		return err
+	}
+
+	switch strings.ToLower(format) {
+	case "text":
+		if gen.block {
+			fmt.Println(strings.Join(values, "\n\n"))
+			return nil
+		}
+		if len(values) == 1 && !gen.record {
+			fmt.Printf("%s: %s\n", gen.label, values[0])
+			for _, note := range gen.notes {
+				fmt.Println(note)
+			}
+			if randomSeeded {
+				fmt.Println(seedWarning)
+			}
+			return nil
+		}
+		fallthrough
+	case "lines", "ndjson":
+		for _, value := range values {
+			fmt.Println(value)
+		}
+	case "json":
+		items := make([]interface{}, len(values))
+		for i, value := range values {
+			items[i] = value
+			if gen.numeric {
+				items[i] = json.Number(value)
+			}
+			if gen.record {
+				items[i] = json.RawMessage(value)
+			}
+		}
+		output, err := json.MarshalIndent(items, "", "  ")
 		if err != nil {
-			return "", err
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		b[i] = charset[n.Int64()]
+		fmt.Println(string(output))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		column := gen.column
+		if column == "" {
+			column = strings.ToLower(randomType)
+		}
+		if err := w.Write([]string{column}); err != nil {
+			return err
+		}
+		for _, value := range values {
+			if err := w.Write([]string{value}); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: text, lines, ndjson, json, csv)", format)
 	}
-	return string(b), nil
+	return nil
+}
+
+func generateRandomString(length int) (string, error) {
+	policy := stringPolicy{charset: []rune(randomAlphabets["alphanumeric"])}
+	return policy.generate(length)
 }
 
 func generateRandomNumber(minVal, maxVal int64) (int64, error) {
-	diff := maxVal - minVal
-	n, err := rand.Int(rand.Reader, big.NewInt(diff))
+	n, err := generateRandomBigInt(big.NewInt(minVal), big.NewInt(maxVal))
 	if err != nil {
 		return 0, err
 	}
-	return minVal + n.Int64(), nil
+	return n.Int64(), nil
 }
 
 func generateUUID() (string, error) {
 	b := make([]byte, 16)
-	_, err := rand.Read(b)
-	if err != nil {
+	if err := randomBytes(b); err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	lowerChars     = "abcdefghijklmnopqrstuvwxyz"
+	upperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+	symbolChars    = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	ambiguousChars = "0O1lI|"
+)
+
+// randomAlphabets are the named presets accepted by --alphabet.
+var randomAlphabets = map[string]string{
+	"alphanumeric": lowerChars + upperChars + digitChars,
+	"alpha":        lowerChars + upperChars,
+	"lower":        lowerChars,
+	"upper":        upperChars,
+	"numeric":      digitChars,
+	"hex":          "0123456789abcdef",
+	"base32":       "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567",
+	"password":     lowerChars + upperChars + digitChars + symbolChars,
+}
+
+// charClasses are the character classes a --require policy can name.
+var charClasses = map[string]func(rune) bool{
+	"lower":  unicode.IsLower,
+	"upper":  unicode.IsUpper,
+	"digit":  unicode.IsDigit,
+	"symbol": func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) },
+}
+
+// stringPolicy describes the alphabet and the character classes that must
+// each appear at least once in a generated string.
+type stringPolicy struct {
+	charset []rune
+	require []string
+}
+
+// newStringPolicy resolves the alphabet preset or custom charset and
+// validates that every required class can be satisfied.
+func newStringPolicy(alphabet, custom string, excludeAmbiguous bool, require []string) (stringPolicy, error) {
+	source := custom
+	if source == "" {
+		preset, ok := randomAlphabets[strings.ToLower(alphabet)]
+		if !ok {
+			return stringPolicy{}, fmt.Errorf("unsupported alphabet: %s (supported: %s)", alphabet, strings.Join(alphabetNames(), ", "))
+		}
+		source = preset
+	}
+
+	seen := make(map[rune]bool)
+	var charset []rune
+	for _, r := range source {
+		if seen[r] || (excludeAmbiguous && strings.ContainsRune(ambiguousChars, r)) {
+			continue
+		}
+		seen[r] = true
+		charset = append(charset, r)
+	}
+	if len(charset) == 0 {
+		return stringPolicy{}, fmt.Errorf("charset is empty")
+	}
+
+	policy := stringPolicy{charset: charset}
+	for _, class := range require {
+		class = strings.ToLower(strings.TrimSpace(class))
+		inClass, ok := charClasses[class]
+		if !ok {
+			return stringPolicy{}, fmt.Errorf("unsupported character class: %s (supported: lower, upper, digit, symbol)", class)
+		}
+		if countMatching(charset, inClass) == 0 {
+			return stringPolicy{}, fmt.Errorf("charset has no %s characters to satisfy the policy", class)
+		}
+		policy.require = append(policy.require, class)
+	}
+	return policy, nil
+}
+
+func alphabetNames() []string {
+	names := make([]string, 0, len(randomAlphabets))
+	for name := range randomAlphabets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func countMatching(charset []rune, match func(rune) bool) int {
+	n := 0
+	for _, r := range charset {
+		if match(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// satisfied reports whether s contains every required character class.
+func (p stringPolicy) satisfied(s []rune) bool {
+	for _, class := range p.require {
+		if countMatching(s, charClasses[class]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// combinations counts the strings of the given length that satisfy the
+// policy, using inclusion-exclusion over the required classes.
+func (p stringPolicy) combinations(length int) *big.Int {
+	total := new(big.Int)
+	for mask := 0; mask < 1<<len(p.require); mask++ {
+		excluded := 0
+		for _, r := range p.charset {
+			for i, class := range p.require {
+				if mask&(1<<i) != 0 && charClasses[class](r) {
+					excluded++
+					break
+				}
+			}
+		}
+		term := new(big.Int).Exp(big.NewInt(int64(len(p.charset)-excluded)), big.NewInt(int64(length)), nil)
+		if popcount(mask)%2 == 1 {
+			total.Sub(total, term)
+		} else {
+			total.Add(total, term)
+		}
+	}
+	return total
+}
+
+// entropy returns the entropy in bits of a uniformly chosen string that
+// satisfies the policy.
+func (p stringPolicy) entropy(length int) float64 {
+	return log2Big(p.combinations(length))
+}
+
+// generate draws strings uniformly from the charset until one satisfies the
+// policy, so every valid string remains equally likely.
+func (p stringPolicy) generate(length int) (string, error) {
+	valid := p.combinations(length)
+	if valid.Sign() == 0 {
+		return "", fmt.Errorf("length %d is too short to satisfy the policy", length)
+	}
+	all := new(big.Int).Exp(big.NewInt(int64(len(p.charset))), big.NewInt(int64(length)), nil)
+	if log2Big(all)-log2Big(valid) > 20 {
+		return "", fmt.Errorf("policy is too restrictive for length %d", length)
+	}
+
+	b := make([]rune, length)
+	for {
+		for i := range b {
+			n, err := randomIndex(len(p.charset))
+			if err != nil {
+				return "", err
+			}
+			b[i] = p.charset[n]
+		}
+		if p.satisfied(b) {
+			return string(b), nil
+		}
+	}
+}
+
+func popcount(x int) int {
+	n := 0
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return n
+}
+
+// log2Big returns log2(x) for a positive big integer without overflowing
+// float64.
+func log2Big(x *big.Int) float64 {
+	if x.Sign() <= 0 {
+		return 0
+	}
+	shift := x.BitLen() - 53
+	if shift <= 0 {
+		f, _ := new(big.Float).SetInt(x).Float64()
+		return math.Log2(f)
+	}
+	f, _ := new(big.Float).SetInt(new(big.Int).Rsh(x, uint(shift))).Float64()
+	return math.Log2(f) + float64(shift)
+}
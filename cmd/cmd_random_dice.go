@@ -0,0 +1,401 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxDiceCount = 1000
+	maxDiceSides = 1000000
+	// maxExplosions bounds how often one exploding die can roll again.
+	maxExplosions = 100
+	// maxDiceWork bounds the cost of an exact distribution, roughly
+	// dice^2 * sides^2 per term.
+	maxDiceWork = 1e9
+)
+
+// diceTerm is one part of a dice expression: either a constant or a group
+// of dice such as 4d6kh3.
+type diceTerm struct {
+	text     string
+	sign     int
+	constant int
+	count    int
+	sides    int
+	explode  bool
+	// keep is "kh" or "kl" with keepCount dice kept; drops are stored as the
+	// equivalent keep.
+	keep      string
+	keepCount int
+}
+
+// diceExpr is a parsed expression such as "4d6kh3+2" or "d20+1d4-1".
+type diceExpr struct {
+	text  string
+	terms []diceTerm
+}
+
+// parseDice parses standard dice notation: NdS (N defaults to 1, d% is
+// d100), followed by ! to explode on the highest face, and kN/khN, klN,
+// dN/dlN, or dhN to keep or drop the highest or lowest dice. Terms and
+// integer modifiers are joined with + and -.
+func parseDice(expr string) (diceExpr, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(expr), ""))
+	if s == "" {
+		return diceExpr{}, fmt.Errorf("empty dice expression")
+	}
+	d := diceExpr{text: s}
+
+	pos := 0
+	number := func() (int, bool) {
+		start := pos
+		for pos < len(s) && s[pos] >= '0' && s[pos] <= '9' && pos-start < 8 {
+			pos++
+		}
+		n, err := strconv.Atoi(s[start:pos])
+		return n, err == nil
+	}
+
+	for pos < len(s) {
+		term := diceTerm{sign: 1}
+		switch {
+		case s[pos] == '+':
+			pos++
+		case s[pos] == '-':
+			term.sign = -1
+			pos++
+		case len(d.terms) > 0:
+			return diceExpr{}, fmt.Errorf("invalid dice expression %q: expected + or - at %q", expr, s[pos:])
+		}
+		start := pos
+
+		n, hasCount := number()
+		if pos >= len(s) || s[pos] != 'd' {
+			if !hasCount {
+				return diceExpr{}, fmt.Errorf("invalid dice expression %q: expected a number or dice at %q", expr, s[start:])
+			}
+			term.constant = n
+			term.text = s[start:pos]
+			d.terms = append(d.terms, term)
+			continue
+		}
+
+		term.count = 1
+		if hasCount {
+			term.count = n
+		}
+		pos++
+		if pos < len(s) && s[pos] == '%' {
+			term.sides = 100
+			pos++
+		} else if term.sides, _ = number(); term.sides == 0 {
+			return diceExpr{}, fmt.Errorf("invalid dice expression %q: dice need a number of sides", expr)
+		}
+
+		for pos < len(s) && s[pos] != '+' && s[pos] != '-' {
+			mod := s[pos]
+			pos++
+			switch mod {
+			case '!':
+				term.explode = true
+				continue
+			case 'k', 'd':
+			default:
+				return diceExpr{}, fmt.Errorf("invalid dice expression %q: unexpected %q", expr, mod)
+			}
+			if term.keep != "" {
+				return diceExpr{}, fmt.Errorf("invalid dice expression %q: only one keep or drop per dice group", expr)
+			}
+			which := byte('h')
+			if mod == 'd' {
+				which = 'l'
+			}
+			if pos < len(s) && (s[pos] == 'h' || s[pos] == 'l') {
+				which = s[pos]
+				pos++
+			}
+			k, ok := number()
+			if !ok {
+				k = 1
+			}
+			if mod == 'd' {
+				// Dropping the lowest is keeping the highest of the rest.
+				k = term.count - k
+				which = map[byte]byte{'h': 'l', 'l': 'h'}[which]
+			}
+			if k < 1 || k > term.count {
+				return diceExpr{}, fmt.Errorf("invalid dice expression %q: must keep between 1 and %d dice", expr, term.count)
+			}
+			term.keep, term.keepCount = "k"+string(which), k
+		}
+
+		switch {
+		case term.count < 1 || term.count > maxDiceCount:
+			return diceExpr{}, fmt.Errorf("dice count must be between 1 and %d", maxDiceCount)
+		case term.sides > maxDiceSides:
+			return diceExpr{}, fmt.Errorf("dice can have at most %d sides", maxDiceSides)
+		case term.explode && term.sides == 1:
+			return diceExpr{}, fmt.Errorf("one-sided dice cannot explode")
+		}
+		term.text = s[start:pos]
+		d.terms = append(d.terms, term)
+	}
+	if len(d.terms) == 0 {
+		return diceExpr{}, fmt.Errorf("invalid dice expression %q", expr)
+	}
+	return d, nil
+}
+
+// newDiceGenerator returns a generator of dice totals. The dice of the most
+// recent roll are kept in the note, so a single roll is printed with them.
+func newDiceGenerator(expr string) (randomGenerator, error) {
+	d, err := parseDice(expr)
+	if err != nil {
+		return randomGenerator{}, err
+	}
+	notes := []string{""}
+	return randomGenerator{
+		name:    "dice roll",
+		label:   fmt.Sprintf("Dice roll (%s)", d.text),
+		notes:   notes,
+		column:  "total",
+		numeric: true,
+		next: func() (string, error) {
+			total, rolls, err := d.roll()
+			if err != nil {
+				return "", err
+			}
+			notes[0] = "Rolls: " + rolls
+			return strconv.Itoa(total), nil
+		},
+	}, nil
+}
+
+// roll rolls every term and returns the total with a breakdown such as
+// "4d6kh3 [6 5 3 (1)] + 2", where dropped dice are in parentheses and dice
+// that exploded are marked with !. Explosion rolls join the pool as dice of
+// their own, and keeps still keep exactly the requested number of them.
+func (d diceExpr) roll() (int, string, error) {
+	total := 0
+	var parts []string
+	for i, term := range d.terms {
+		sign := "+"
+		if term.sign < 0 {
+			sign = "-"
+		}
+		if i > 0 || term.sign < 0 {
+			parts = append(parts, sign)
+		}
+		if term.count == 0 {
+			total += term.sign * term.constant
+			parts = append(parts, strconv.Itoa(term.constant))
+			continue
+		}
+
+		var rolls []int
+		for n := 0; n < term.count; n++ {
+			for extra := 0; ; extra++ {
+				v, err := randomIndex(term.sides)
+				if err != nil {
+					return 0, "", err
+				}
+				rolls = append(rolls, v+1)
+				if !term.explode || v+1 < term.sides || extra == maxExplosions {
+					break
+				}
+			}
+		}
+
+		kept := make([]bool, len(rolls))
+		order := make([]int, len(rolls))
+		for k := range order {
+			order[k] = k
+			kept[k] = term.keep == ""
+		}
+		if term.keep != "" {
+			// Exploded dice join the pool, but the pool still keeps
+			// keepCount dice: 4d6!kh3 keeps the best three rolls.
+			keep := min(term.keepCount, len(rolls))
+			sort.SliceStable(order, func(a, b int) bool {
+				if term.keep == "kh" {
+					return rolls[order[a]] > rolls[order[b]]
+				}
+				return rolls[order[a]] < rolls[order[b]]
+			})
+			for _, k := range order[:keep] {
+				kept[k] = true
+			}
+		}
+
+		shown := make([]string, len(rolls))
+		for k, v := range rolls {
+			shown[k] = strconv.Itoa(v)
+			if term.explode && v == term.sides {
+				shown[k] += "!"
+			}
+			if kept[k] {
+				total += term.sign * v
+			} else {
+				shown[k] = "(" + shown[k] + ")"
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s]", term.text, strings.Join(shown, " ")))
+	}
+	return total, strings.Join(parts, " "), nil
+}
+
+// diceDistribution maps each possible total to its probability.
+type diceDistribution map[int]float64
+
+// distribution computes the exact probability of every total. Exploding
+// dice have no largest total, so outcomes after more than maxExplosions
+// explosions, or rarer than 1e-15, are left out; truncated reports this.
+func (d diceExpr) distribution() (dist diceDistribution, truncated bool, err error) {
+	dist = diceDistribution{0: 1}
+	for _, term := range d.terms {
+		var part diceDistribution
+		switch {
+		case term.count == 0:
+			part = diceDistribution{term.constant: 1}
+		case float64(term.count)*float64(term.count)*float64(term.sides)*float64(term.sides) > maxDiceWork:
+			return nil, false, fmt.Errorf("%s has too many outcomes for an exact distribution", term.text)
+		case term.keep != "" && term.explode:
+			return nil, false, fmt.Errorf("exact distributions of exploding dice with keep or drop are not supported")
+		case term.keep != "":
+			if float64(term.count)*math.Log10(float64(term.sides)) > 300 {
+				return nil, false, fmt.Errorf("%s has too many outcomes for an exact distribution", term.text)
+			}
+			part = keepDistribution(term.count, term.sides, term.keepCount, term.keep == "kh")
+		default:
+			die := diceDistribution{}
+			for v := 1; v <= term.sides; v++ {
+				die[v] = 1 / float64(term.sides)
+			}
+			if term.explode {
+				die, truncated = explodingDie(term.sides)
+			}
+			part = diceDistribution{0: 1}
+			for n := 0; n < term.count; n++ {
+				part = part.add(die, 1)
+			}
+		}
+		dist = dist.add(part, term.sign)
+	}
+	return dist, truncated, nil
+}
+
+// add returns the distribution of a + sign*b.
+func (a diceDistribution) add(b diceDistribution, sign int) diceDistribution {
+	sum := make(diceDistribution, len(a)+len(b))
+	for x, p := range a {
+		for y, q := range b {
+			sum[x+sign*y] += p * q
+		}
+	}
+	return sum
+}
+
+// explodingDie is the distribution of one exploding die: each maximum
+// roll adds another roll, so a total of m*sides + r has probability
+// (1/sides)^(m+1) for r < sides.
+func explodingDie(sides int) (diceDistribution, bool) {
+	die := diceDistribution{}
+	p := 1 / float64(sides)
+	for m := 0; m <= maxExplosions && p > 1e-15; m++ {
+		for r := 1; r < sides; r++ {
+			die[m*sides+r] = p
+		}
+		p /= float64(sides)
+	}
+	return die, true
+}
+
+// keepDistribution is the distribution of the sum of the keep highest (or
+// lowest) of count dice. Faces are assigned from the best down; ways[a][t]
+// counts the ways a dice can show the faces seen so far with t kept.
+func keepDistribution(count, sides, keep int, highest bool) diceDistribution {
+	ways := make([]map[int]float64, count+1)
+	for a := range ways {
+		ways[a] = make(map[int]float64)
+	}
+	ways[0][0] = 1
+
+	for step := 0; step < sides; step++ {
+		face := step + 1
+		if highest {
+			face = sides - step
+		}
+		next := make([]map[int]float64, count+1)
+		for a := range next {
+			next[a] = make(map[int]float64)
+		}
+		for a, sums := range ways {
+			for t, w := range sums {
+				for c := 0; a+c <= count; c++ {
+					kept := max(min(c, keep-a), 0)
+					next[a+c][t+kept*face] += w * binomial(count-a, c)
+				}
+			}
+		}
+		ways = next
+	}
+
+	total := math.Pow(float64(sides), float64(count))
+	dist := diceDistribution{}
+	for t, w := range ways[count] {
+		dist[t] = w / total
+	}
+	return dist
+}
+
+// formatDiceDistribution renders a table of totals with their probability,
+// the chance of rolling at least that total, and a bar.
+func formatDiceDistribution(d diceExpr, dist diceDistribution, truncated bool) string {
+	totals := make([]int, 0, len(dist))
+	mean, peak := 0.0, 0.0
+	for t, p := range dist {
+		if p > 0 {
+			totals = append(totals, t)
+		}
+		mean += float64(t) * p
+		peak = math.Max(peak, p)
+	}
+	sort.Ints(totals)
+	variance := 0.0
+	for t, p := range dist {
+		variance += (float64(t) - mean) * (float64(t) - mean) * p
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Distribution of %s (mean %.2f, std dev %.2f)\n", d.text, mean, math.Sqrt(variance))
+	width := max(len("Total"), len(strconv.Itoa(totals[0])), len(strconv.Itoa(totals[len(totals)-1])))
+	fmt.Fprintf(&b, "%*s  %9s  %9s\n", width, "Total", "Chance", "At least")
+	atLeast := 0.0
+	for _, p := range dist {
+		atLeast += p
+	}
+	hidden := 0
+	for _, t := range totals {
+		p := dist[t]
+		// Skip totals too rare to show at this precision.
+		if p < 5e-7 {
+			hidden++
+			atLeast -= p
+			continue
+		}
+		line := fmt.Sprintf("%*d  %8.4f%%  %8.4f%%  %s", width, t, 100*p, 100*atLeast, strings.Repeat("#", int(math.Round(40*p/peak))))
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+		atLeast -= p
+	}
+	if hidden > 0 {
+		fmt.Fprintf(&b, "%d totals with a chance below 0.00005%% are not shown\n", hidden)
+	}
+	if truncated {
+		b.WriteString("Exploding dice: outcomes rarer than 1e-15 are omitted\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var randomDecodeCmd = &cobra.Command{
+	Use:   "decode [id]",
+	Short: "Extract the timestamp and fields embedded in an ID",
+	Long: `Decode a ULID, KSUID, Snowflake, MongoDB ObjectID, or time-based UUID and
+print its embedded timestamp using the same formatting and timezone handling
+as the time command. The ID type is detected automatically unless --type is
+given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRandomDecode,
+}
+
+var (
+	decodeType       string
+	decodeEpoch      int64
+	decodeTimeFormat string
+	decodeTimezone   string
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62Alphabet    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	nanoidAlphabet    = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	nanoidSize        = 21
+
+	// ksuidEpoch is the KSUID epoch (2014-05-13T16:53:20Z) in Unix seconds.
+	ksuidEpoch = 1400000000
+	// twitterEpoch is the default Snowflake epoch (2010-11-04T01:42:54.657Z)
+	// in Unix milliseconds.
+	twitterEpoch = 1288834974657
+	maxWorkerID  = 1<<10 - 1
+)
+
+func init() {
+	randomDecodeCmd.Flags().StringVarP(&decodeType, "type", "t", "auto", "ID type: auto, ulid, ksuid, snowflake, objectid, uuid")
+	randomDecodeCmd.Flags().Int64Var(&decodeEpoch, "epoch", twitterEpoch, "Snowflake epoch in Unix milliseconds")
+	randomDecodeCmd.Flags().StringVarP(&decodeTimeFormat, "format", "f", "2006-01-02 15:04:05.000", "Output format for embedded timestamps")
+	randomDecodeCmd.Flags().StringVarP(&decodeTimezone, "timezone", "z", "UTC", "Timezone for embedded timestamps (UTC, Local, or IANA name)")
+	randomCmd.AddCommand(randomDecodeCmd)
+}
+
+// generateULID returns a 26 character Crockford base32 ULID: a 48-bit
+// millisecond timestamp followed by 80 random bits.
+func generateULID(now time.Time) (string, error) {
+	b := make([]byte, 16)
+	if err := randomBytes(b[6:]); err != nil {
+		return "", err
+	}
+	ms := uint64(now.UnixMilli())
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	return encodeBase(new(big.Int).SetBytes(b), crockfordAlphabet, 26), nil
+}
+
+// generateKSUID returns a 27 character base62 KSUID: a 32-bit timestamp in
+// seconds since the KSUID epoch followed by 128 random bits.
+func generateKSUID(now time.Time) (string, error) {
+	b := make([]byte, 20)
+	if err := randomBytes(b[4:]); err != nil {
+		return "", err
+	}
+	binary.BigEndian.PutUint32(b[:4], uint32(now.Unix()-ksuidEpoch))
+	return encodeBase(new(big.Int).SetBytes(b), base62Alphabet, 27), nil
+}
+
+// generateNanoID returns a NanoID of the given size over alphabet.
+func generateNanoID(alphabet []rune, size int) (string, error) {
+	if size <= 0 {
+		return "", fmt.Errorf("size must be positive")
+	}
+	return stringPolicy{charset: alphabet}.generate(size)
+}
+
+// snowflakeState keeps the per-millisecond sequence so IDs generated in the
+// same process stay unique.
+var snowflakeState struct {
+	sync.Mutex
+	lastMs   int64
+	sequence int64
+}
+
+// generateSnowflake returns a Twitter-style Snowflake: 41 bits of
+// milliseconds since epoch, a 10-bit worker ID, and a 12-bit sequence.
+func generateSnowflake(now time.Time, epoch, worker int64) (string, error) {
+	if worker < 0 || worker > maxWorkerID {
+		return "", fmt.Errorf("worker ID must be between 0 and %d", maxWorkerID)
+	}
+	ms := now.UnixMilli() - epoch
+	if ms < 0 || ms >= 1<<41 {
+		return "", fmt.Errorf("current time is outside the range of epoch %d", epoch)
+	}
+
+	snowflakeState.Lock()
+	defer snowflakeState.Unlock()
+	if ms <= snowflakeState.lastMs {
+		ms = snowflakeState.lastMs
+		snowflakeState.sequence = (snowflakeState.sequence + 1) & 0xfff
+		if snowflakeState.sequence == 0 {
+			ms++
+		}
+	} else {
+		snowflakeState.sequence = 0
+	}
+	snowflakeState.lastMs = ms
+
+	id := ms<<22 | worker<<12 | snowflakeState.sequence
+	return strconv.FormatInt(id, 10), nil
+}
+
+// objectIDState holds the per-process random value and counter of MongoDB
+// ObjectIDs.
+var objectIDState struct {
+	sync.Once
+	sync.Mutex
+	random  [5]byte
+	counter uint32
+	err     error
+}
+
+// generateObjectID returns a 24 character hex MongoDB ObjectID: a 32-bit
+// timestamp in seconds, a 5-byte per-process random value, and a 3-byte
+// counter.
+func generateObjectID(now time.Time) (string, error) {
+	objectIDState.Do(func() {
+		var seed [4]byte
+		if err := randomBytes(objectIDState.random[:]); err != nil {
+			objectIDState.err = err
+			return
+		}
+		if err := randomBytes(seed[:]); err != nil {
+			objectIDState.err = err
+			return
+		}
+		objectIDState.counter = binary.BigEndian.Uint32(seed[:])
+	})
+	if objectIDState.err != nil {
+		return "", objectIDState.err
+	}
+
+	objectIDState.Lock()
+	objectIDState.counter++
+	counter := objectIDState.counter
+	objectIDState.Unlock()
+
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint32(b[0:4], uint32(now.Unix()))
+	copy(b[4:9], objectIDState.random[:])
+	b[9], b[10], b[11] = byte(counter>>16), byte(counter>>8), byte(counter)
+	return hex.EncodeToString(b), nil
+}
+
+// encodeBase renders n in the given alphabet, left padded to width.
+func encodeBase(n *big.Int, alphabet string, width int) string {
+	base := big.NewInt(int64(len(alphabet)))
+	out := make([]byte, width)
+	v := new(big.Int).Set(n)
+	mod := new(big.Int)
+	for i := width - 1; i >= 0; i-- {
+		v.DivMod(v, base, mod)
+		out[i] = alphabet[mod.Int64()]
+	}
+	return string(out)
+}
+
+// decodeBase parses s in the given alphabet.
+func decodeBase(s, alphabet string) (*big.Int, error) {
+	base := big.NewInt(int64(len(alphabet)))
+	n := new(big.Int)
+	for _, r := range s {
+		i := strings.IndexRune(alphabet, r)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid character %q", r)
+		}
+		n.Mul(n, base).Add(n, big.NewInt(int64(i)))
+	}
+	return n, nil
+}
+
+var (
+	// ulidPattern accepts I, L, and O, which Crockford base32 decodes as
+	// 1, 1, and 0.
+	ulidPattern      = regexp.MustCompile(`^[0-7][0-9A-TV-Za-tv-z]{25}$`)
+	ksuidPattern     = regexp.MustCompile(`^[0-9A-Za-z]{27}$`)
+	objectIDPattern  = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+	snowflakePattern = regexp.MustCompile(`^[0-9]{1,19}$`)
+)
+
+// detectIDType guesses the scheme of an ID from its shape.
+func detectIDType(id string) (string, error) {
+	switch {
+	case ulidPattern.MatchString(id):
+		return "ulid", nil
+	case ksuidPattern.MatchString(id):
+		return "ksuid", nil
+	case objectIDPattern.MatchString(id):
+		return "objectid", nil
+	case snowflakePattern.MatchString(id):
+		return "snowflake", nil
+	}
+	if _, err := parseUUID(id); err == nil {
+		return "uuid", nil
+	}
+	return "", fmt.Errorf("unrecognized ID format: %s (use --type to specify)", id)
+}
+
+// decodedID is the timestamp and labelled fields extracted from an ID.
+type decodedID struct {
+	kind      string
+	timestamp time.Time
+	fields    [][2]string
+}
+
+func decodeID(id, kind string, epoch int64) (decodedID, error) {
+	switch kind {
+	case "ulid":
+		normalized := strings.NewReplacer("I", "1", "L", "1", "O", "0").Replace(strings.ToUpper(id))
+		if len(normalized) != 26 {
+			return decodedID{}, fmt.Errorf("invalid ULID: must be 26 characters")
+		}
+		n, err := decodeBase(normalized, crockfordAlphabet)
+		if err != nil || n.BitLen() > 128 {
+			return decodedID{}, fmt.Errorf("invalid ULID: %s", id)
+		}
+		b := n.FillBytes(make([]byte, 16))
+		ms := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, b[:6]...)))
+		return decodedID{
+			kind:      "ULID",
+			timestamp: time.UnixMilli(ms),
+			fields: [][2]string{
+				{"Unix milliseconds", strconv.FormatInt(ms, 10)},
+				{"Randomness", hex.EncodeToString(b[6:])},
+			},
+		}, nil
+	case "ksuid":
+		n, err := decodeBase(id, base62Alphabet)
+		if err != nil || len(id) != 27 || n.BitLen() > 160 {
+			return decodedID{}, fmt.Errorf("invalid KSUID: %s", id)
+		}
+		b := n.FillBytes(make([]byte, 20))
+		secs := int64(binary.BigEndian.Uint32(b[:4])) + ksuidEpoch
+		return decodedID{
+			kind:      "KSUID",
+			timestamp: time.Unix(secs, 0),
+			fields: [][2]string{
+				{"Unix seconds", strconv.FormatInt(secs, 10)},
+				{"Payload", hex.EncodeToString(b[4:])},
+			},
+		}, nil
+	case "snowflake":
+		v, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || v < 0 {
+			return decodedID{}, fmt.Errorf("invalid Snowflake: %s", id)
+		}
+		ms := v>>22 + epoch
+		return decodedID{
+			kind:      "Snowflake",
+			timestamp: time.UnixMilli(ms),
+			fields: [][2]string{
+				{"Unix milliseconds", strconv.FormatInt(ms, 10)},
+				{"Worker", strconv.FormatInt(v>>12&maxWorkerID, 10)},
+				{"Sequence", strconv.FormatInt(v&0xfff, 10)},
+			},
+		}, nil
+	case "objectid":
+		b, err := hex.DecodeString(id)
+		if err != nil || len(b) != 12 {
+			return decodedID{}, fmt.Errorf("invalid ObjectID: %s", id)
+		}
+		secs := int64(binary.BigEndian.Uint32(b[:4]))
+		counter := uint32(b[9])<<16 | uint32(b[10])<<8 | uint32(b[11])
+		return decodedID{
+			kind:      "ObjectID",
+			timestamp: time.Unix(secs, 0),
+			fields: [][2]string{
+				{"Unix seconds", strconv.FormatInt(secs, 10)},
+				{"Random", hex.EncodeToString(b[4:9])},
+				{"Counter", strconv.FormatUint(uint64(counter), 10)},
+			},
+		}, nil
+	case "uuid":
+		b, err := parseUUID(id)
+		if err != nil {
+			return decodedID{}, err
+		}
+		version := int(b[6] >> 4)
+		switch {
+		case uuidVariant(b) != "RFC 9562":
+			return decodedID{}, fmt.Errorf("UUID has no embedded timestamp")
+		case version == 1 || version == 6:
+			return decodedID{kind: fmt.Sprintf("UUID v%d", version), timestamp: uuidGregorianTime(b, version)}, nil
+		case version == 7:
+			ms := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, b[:6]...)))
+			return decodedID{kind: "UUID v7", timestamp: time.UnixMilli(ms)}, nil
+		default:
+			return decodedID{}, fmt.Errorf("UUID v%d has no embedded timestamp", version)
+		}
+	case "nanoid":
+		return decodedID{}, fmt.Errorf("NanoIDs are fully random and embed no timestamp")
+	default:
+		return decodedID{}, fmt.Errorf("unsupported ID type: %s (supported: auto, ulid, ksuid, snowflake, objectid, uuid)", kind)
+	}
+}
+
+func runRandomDecode(cmd *cobra.Command, args []string) error {
+	location, err := parseTimezone(decodeTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	id := strings.TrimSpace(args[0])
+	kind := strings.ToLower(decodeType)
+	if kind == "auto" {
+		kind, err = detectIDType(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	decoded, err := decodeID(id, kind, decodeEpoch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Type: %s\n", decoded.kind)
+	fmt.Printf("Timestamp: %s (%s)\n", formatTime(decoded.timestamp, location, decodeTimeFormat), decodeTimezone)
+	for _, field := range decoded.fields {
+		fmt.Printf("%s: %s\n", field[0], field[1])
+	}
+	return nil
+}
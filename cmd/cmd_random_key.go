@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var randomKeypairCmd = &cobra.Command{
+	Use:   "keypair",
+	Short: "Generate an Ed25519, ECDSA P-256, or RSA keypair",
+	Long: `Generate a keypair and print the private key followed by the public key.
+
+PEM output uses PKCS#8 for the private key and PKIX for the public key.
+OpenSSH output uses the unencrypted "openssh-key-v1" private key format and
+an authorized_keys line for the public key. With --out the keys are written
+to the file (mode 0600) and to the file with a .pub suffix instead.
+
+Keys always come from the system CSPRNG.`,
+	Args: cobra.NoArgs,
+	RunE: runRandomKeypair,
+}
+
+var (
+	keypairType    string
+	keypairBits    int
+	keypairFormat  string
+	keypairComment string
+	keypairOut     string
+)
+
+func init() {
+	randomKeypairCmd.Flags().StringVarP(&keypairType, "type", "t", "ed25519", "Key type: ed25519, ecdsa (P-256), rsa")
+	randomKeypairCmd.Flags().IntVarP(&keypairBits, "bits", "b", 3072, "RSA key size in bits (2048-8192)")
+	randomKeypairCmd.Flags().StringVarP(&keypairFormat, "format", "f", "pem", "Key format: pem, openssh")
+	randomKeypairCmd.Flags().StringVarP(&keypairComment, "comment", "C", "", "Comment for OpenSSH keys")
+	randomKeypairCmd.Flags().StringVarP(&keypairOut, "out", "o", "", "Write the private key to this file and the public key to <file>.pub")
+	randomCmd.AddCommand(randomKeypairCmd)
+}
+
+func runRandomKeypair(cmd *cobra.Command, args []string) error {
+	key, err := generateKeyPair(keypairType, keypairBits)
+	if err != nil {
+		return err
+	}
+
+	var private, public []byte
+	switch strings.ToLower(keypairFormat) {
+	case "pem":
+		private, public, err = marshalPEMKeys(key)
+	case "openssh":
+		private, public, err = marshalOpenSSHKeys(key, keypairComment)
+	default:
+		return fmt.Errorf("unsupported key format: %s (supported: pem, openssh)", keypairFormat)
+	}
+	if err != nil {
+		return err
+	}
+
+	if keypairOut == "" {
+		fmt.Print(string(private))
+		fmt.Print(string(public))
+		return nil
+	}
+
+	if err := os.WriteFile(keypairOut, private, 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(keypairOut+".pub", public, 0644); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	blob, err := sshPublicKeyBlob(key.Public())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Private key: %s\n", keypairOut)
+	fmt.Printf("Public key: %s.pub\n", keypairOut)
+	fmt.Printf("Fingerprint: %s\n", sshFingerprint(blob))
+	return nil
+}
+
+// generateKeyPair creates a private key of the given type.
+func generateKeyPair(keyType string, bits int) (crypto.Signer, error) {
+	switch strings.ToLower(keyType) {
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	case "ecdsa", "p256", "p-256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "rsa":
+		if bits < 2048 || bits > 8192 {
+			return nil, fmt.Errorf("RSA key size must be between 2048 and 8192 bits")
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s (supported: ed25519, ecdsa, rsa)", keyType)
+	}
+}
+
+// marshalPEMKeys encodes key as a PKCS#8 private key and a PKIX public key.
+func marshalPEMKeys(key crypto.Signer) ([]byte, []byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), nil
+}
+
+// marshalOpenSSHKeys encodes key in the unencrypted openssh-key-v1 format
+// (PROTOCOL.key in the OpenSSH sources) and as an authorized_keys line.
+func marshalOpenSSHKeys(key crypto.Signer, comment string) ([]byte, []byte, error) {
+	blob, err := sshPublicKeyBlob(key.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, nil, err
+	}
+	var priv sshWriter
+	priv.Write(check[:])
+	priv.Write(check[:])
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		pub := k.Public().(ed25519.PublicKey)
+		priv.string([]byte("ssh-ed25519"))
+		priv.string(pub)
+		priv.string(k)
+	case *ecdsa.PrivateKey:
+		ecdhKey, err := k.ECDH()
+		if err != nil {
+			return nil, nil, err
+		}
+		priv.string([]byte("ecdsa-sha2-nistp256"))
+		priv.string([]byte("nistp256"))
+		priv.string(ecdhKey.PublicKey().Bytes())
+		priv.mpint(new(big.Int).SetBytes(ecdhKey.Bytes()))
+	case *rsa.PrivateKey:
+		priv.string([]byte("ssh-rsa"))
+		priv.mpint(k.N)
+		priv.mpint(big.NewInt(int64(k.E)))
+		priv.mpint(k.D)
+		priv.mpint(k.Precomputed.Qinv)
+		priv.mpint(k.Primes[0])
+		priv.mpint(k.Primes[1])
+	}
+	priv.string([]byte(comment))
+	// Pad to the cipher block size, which is 8 for "none".
+	for i := byte(1); priv.Len()%8 != 0; i++ {
+		priv.WriteByte(i)
+	}
+
+	var out sshWriter
+	out.WriteString("openssh-key-v1\x00")
+	out.string([]byte("none"))
+	out.string([]byte("none"))
+	out.string(nil)
+	out.uint32(1)
+	out.string(blob)
+	out.string(priv.Bytes())
+
+	line := sshKeyType(blob) + " " + base64.StdEncoding.EncodeToString(blob)
+	if comment != "" {
+		line += " " + comment
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: out.Bytes()}), []byte(line + "\n"), nil
+}
+
+// sshPublicKeyBlob returns the SSH wire encoding of a public key.
+func sshPublicKeyBlob(pub crypto.PublicKey) ([]byte, error) {
+	var w sshWriter
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		w.string([]byte("ssh-ed25519"))
+		w.string(k)
+	case *ecdsa.PublicKey:
+		ecdhKey, err := k.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		w.string([]byte("ecdsa-sha2-nistp256"))
+		w.string([]byte("nistp256"))
+		w.string(ecdhKey.Bytes())
+	case *rsa.PublicKey:
+		w.string([]byte("ssh-rsa"))
+		w.mpint(big.NewInt(int64(k.E)))
+		w.mpint(k.N)
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return w.Bytes(), nil
+}
+
+// sshKeyType returns the algorithm name at the start of a public key blob.
+func sshKeyType(blob []byte) string {
+	n := binary.BigEndian.Uint32(blob)
+	return string(blob[4 : 4+n])
+}
+
+// sshFingerprint formats a public key blob like "ssh-keygen -l".
+func sshFingerprint(blob []byte) string {
+	sum := sha256.Sum256(blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// sshWriter builds SSH wire-format data (RFC 4251 section 5).
+type sshWriter struct {
+	bytes.Buffer
+}
+
+func (w *sshWriter) uint32(v uint32) {
+	w.Write(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (w *sshWriter) string(b []byte) {
+	w.uint32(uint32(len(b)))
+	w.Write(b)
+}
+
+// mpint writes a non-negative integer in two's complement, with a leading
+// zero byte when the high bit is set.
+func (w *sshWriter) mpint(n *big.Int) {
+	b := n.Bytes()
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	w.string(b)
+}
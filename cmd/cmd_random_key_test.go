@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+func TestKeypairFormats(t *testing.T) {
+	tests := []struct {
+		keyType string
+		sshType string
+	}{
+		{"ed25519", "ssh-ed25519"},
+		{"ecdsa", "ecdsa-sha2-nistp256"},
+		{"rsa", "ssh-rsa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType, func(t *testing.T) {
+			key, err := generateKeyPair(tt.keyType, 2048)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			private, public, err := marshalPEMKeys(key)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			block, _ := pem.Decode(private)
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				t.Fatalf("Failed to parse PKCS#8 key: %v", err)
+			}
+			block, _ = pem.Decode(public)
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				t.Fatalf("Failed to parse PKIX key: %v", err)
+			}
+			keyPub := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+			if !keyPub.Equal(pub) || !keyPub.Equal(parsed.(crypto.Signer).Public()) {
+				t.Error("PEM keys do not match the generated key")
+			}
+
+			private, public, err = marshalOpenSSHKeys(key, "me@host")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			fields := strings.Fields(string(public))
+			if len(fields) != 3 || fields[0] != tt.sshType || fields[2] != "me@host" {
+				t.Fatalf("Unexpected authorized_keys line: %q", public)
+			}
+			blob, _ := base64.StdEncoding.DecodeString(fields[1])
+
+			block, _ = pem.Decode(private)
+			if block == nil || block.Type != "OPENSSH PRIVATE KEY" {
+				t.Fatalf("Expected an OPENSSH PRIVATE KEY block, got %q", private)
+			}
+			r := sshReader{data: block.Bytes}
+			if magic := r.next(15); string(magic) != "openssh-key-v1\x00" {
+				t.Fatalf("Unexpected magic %q", magic)
+			}
+			if cipher, kdf := r.string(), r.string(); string(cipher) != "none" || string(kdf) != "none" {
+				t.Errorf("Expected an unencrypted key, got %s/%s", cipher, kdf)
+			}
+			r.string()
+			if n := binary.BigEndian.Uint32(r.next(4)); n != 1 {
+				t.Errorf("Expected 1 key, got %d", n)
+			}
+			if !bytes.Equal(r.string(), blob) {
+				t.Error("Private key file embeds a different public key")
+			}
+			section := r.string()
+			if len(section)%8 != 0 || !bytes.Equal(section[0:4], section[4:8]) {
+				t.Error("Expected padded private section with matching check integers")
+			}
+			if sshKeyType(section[8:]) != tt.sshType {
+				t.Errorf("Expected private key type %s", tt.sshType)
+			}
+			if !bytes.Contains(section, []byte("me@host")) {
+				t.Error("Expected comment in private section")
+			}
+		})
+	}
+}
+
+func TestKeypairValidation(t *testing.T) {
+	if _, err := generateKeyPair("dsa", 0); err == nil {
+		t.Error("Expected error for unsupported key type")
+	}
+	if _, err := generateKeyPair("rsa", 1024); err == nil {
+		t.Error("Expected error for weak RSA key size")
+	}
+}
+
+func TestSSHFingerprint(t *testing.T) {
+	// Public key and fingerprint as reported by ssh-keygen -l.
+	blob, _ := base64.StdEncoding.DecodeString("AAAAC3NzaC1lZDI1NTE5AAAAIMsFEuvNsfjDXvRXOXw0Pgl9aaqdkryZcAk0c3oft9BL")
+	if got := sshFingerprint(blob); got != "SHA256:JPxyyq7mxC8eoLQA/hjoGEBWHRLVeAN+vtg5/DM9yx8" {
+		t.Errorf("Unexpected fingerprint %s", got)
+	}
+	if got := sshKeyType(blob); got != "ssh-ed25519" {
+		t.Errorf("Expected ssh-ed25519, got %s", got)
+	}
+}
+
+type sshReader struct {
+	data []byte
+}
+
+func (r *sshReader) next(n int) []byte {
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *sshReader) string() []byte {
+	return r.next(int(binary.BigEndian.Uint32(r.next(4))))
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// loremOpening is how classic placeholder text always begins.
+const loremOpening = "lorem ipsum dolor sit amet consectetur adipiscing elit"
+
+// loadLoremWords returns the vocabulary of classic lorem ipsum, the
+// scrambled Cicero ("de Finibus Bonorum et Malorum") used by typesetters
+// since the 1500s. It is the list "plz fake lorem" draws from as well.
+func loadLoremWords() ([]string, error) {
+	var common fakeCommon
+	if err := loadFakeJSON("common", &common); err != nil {
+		return nil, err
+	}
+	return common.LoremWords, nil
+}
+
+// loremWidth is the column at which Markdown paragraphs are wrapped.
+const loremWidth = 80
+
+// loremOptions selects how much placeholder text to write and how.
+type loremOptions struct {
+	// unit is "words", "sentences", or "paragraphs", and count how many.
+	unit  string
+	count int
+	// classic text opens with "Lorem ipsum dolor sit amet..." and uses the
+	// Latin vocabulary; otherwise words come from the passphrase wordlist.
+	classic bool
+	// markup is "text", "html", or "markdown".
+	markup string
+	// wordlist is the file random words are drawn from, or "" for the
+	// embedded list.
+	wordlist string
+}
+
+// newLoremGenerator returns placeholder text generated according to opts.
+func newLoremGenerator(opts loremOptions) (randomGenerator, error) {
+	if opts.count < 1 {
+		return randomGenerator{}, fmt.Errorf("lorem %s count must be at least 1", opts.unit)
+	}
+	switch opts.markup {
+	case "text", "html", "markdown":
+	default:
+		return randomGenerator{}, fmt.Errorf("unsupported markup: %s (supported: text, html, markdown)", opts.markup)
+	}
+
+	vocabulary, err := loadLoremWords()
+	mode := "classic"
+	if !opts.classic {
+		vocabulary, err = loadWordlist(opts.wordlist)
+		mode = "random words"
+	}
+	if err != nil {
+		return randomGenerator{}, err
+	}
+
+	return randomGenerator{
+		name:   "lorem ipsum",
+		label:  fmt.Sprintf("Lorem ipsum (%d %s, %s)", opts.count, opts.unit, mode),
+		column: "text",
+		block:  true,
+		next: func() (string, error) {
+			w := &loremWriter{vocabulary: vocabulary}
+			if opts.classic {
+				w.opening = strings.Fields(loremOpening)
+			}
+			return w.generate(opts)
+		},
+	}, nil
+}
+
+// loremWriter draws words for one block of text, starting with the opening
+// words when there are any.
+type loremWriter struct {
+	vocabulary []string
+	opening    []string
+}
+
+func (w *loremWriter) word() (string, error) {
+	if len(w.opening) > 0 {
+		word := w.opening[0]
+		w.opening = w.opening[1:]
+		return word, nil
+	}
+	n, err := randomIndex(len(w.vocabulary))
+	if err != nil {
+		return "", err
+	}
+	return w.vocabulary[n], nil
+}
+
+// between returns a uniform integer in [lo, hi].
+func between(lo, hi int) (int, error) {
+	n, err := randomIndex(hi - lo + 1)
+	return lo + n, err
+}
+
+// words returns n words as a capitalized phrase without punctuation.
+func (w *loremWriter) words(n int) (string, error) {
+	words := make([]string, n)
+	for i := range words {
+		word, err := w.word()
+		if err != nil {
+			return "", err
+		}
+		words[i] = word
+	}
+	return titleWord(strings.Join(words, " ")), nil
+}
+
+// sentence returns 6 to 15 words, sometimes split by a comma, ending in a
+// full stop. The classic opening always forms a sentence of its own.
+func (w *loremWriter) sentence() (string, error) {
+	n, err := between(6, 15)
+	if err != nil {
+		return "", err
+	}
+	if len(w.opening) > 0 {
+		n = len(w.opening)
+	}
+	comma := 0
+	if n >= 8 {
+		if c, err := randomIndex(2); err != nil {
+			return "", err
+		} else if c == 0 {
+			if comma, err = between(3, n-3); err != nil {
+				return "", err
+			}
+		}
+	}
+	if len(w.opening) > 0 {
+		comma = 5 // Lorem ipsum dolor sit amet, consectetur...
+	}
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		word, err := w.word()
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			if i == comma {
+				b.WriteString(",")
+			}
+			b.WriteString(" ")
+		}
+		b.WriteString(word)
+	}
+	return titleWord(b.String()) + ".", nil
+}
+
+// sentences returns n sentences joined by spaces.
+func (w *loremWriter) sentences(n int) (string, error) {
+	sentences := make([]string, n)
+	for i := range sentences {
+		s, err := w.sentence()
+		if err != nil {
+			return "", err
+		}
+		sentences[i] = s
+	}
+	return strings.Join(sentences, " "), nil
+}
+
+// generate writes one block of text in the requested unit and markup.
+// Words are inline text; sentences form one paragraph.
+func (w *loremWriter) generate(opts loremOptions) (string, error) {
+	if opts.unit == "words" {
+		text, err := w.words(opts.count)
+		if opts.markup == "html" {
+			text = html.EscapeString(text)
+		}
+		return text, err
+	}
+
+	var paragraphs []string
+	if opts.unit == "sentences" {
+		p, err := w.sentences(opts.count)
+		if err != nil {
+			return "", err
+		}
+		paragraphs = append(paragraphs, p)
+	} else {
+		for i := 0; i < opts.count; i++ {
+			n, err := between(4, 8)
+			if err != nil {
+				return "", err
+			}
+			p, err := w.sentences(n)
+			if err != nil {
+				return "", err
+			}
+			paragraphs = append(paragraphs, p)
+		}
+	}
+
+	switch opts.markup {
+	case "html":
+		for i, p := range paragraphs {
+			paragraphs[i] = "<p>" + html.EscapeString(p) + "</p>"
+		}
+		return strings.Join(paragraphs, "\n"), nil
+	case "markdown":
+		for i, p := range paragraphs {
+			paragraphs[i] = wrapWords(p, loremWidth)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// wrapWords breaks text into lines of at most width columns, except for
+// words that are longer on their own.
+func wrapWords(text string, width int) string {
+	var b strings.Builder
+	column := 0
+	for _, word := range strings.Fields(text) {
+		switch {
+		case column == 0:
+		case column+1+utf8.RuneCountInString(word) > width:
+			b.WriteString("\n")
+			column = 0
+		default:
+			b.WriteString(" ")
+			column++
+		}
+		b.WriteString(word)
+		column += utf8.RuneCountInString(word)
+	}
+	return b.String()
+}
+
+// loremOptionsFromFlags reads the lorem unit from whichever of
+// --lorem-words, --sentences, and --paragraphs was given, defaulting to
+// paragraphs.
+func loremOptionsFromFlags(cmd *cobra.Command) (loremOptions, error) {
+	opts := loremOptions{unit: "paragraphs", count: randomParagraphs, markup: strings.ToLower(randomMarkup), wordlist: randomWordlist}
+	set := 0
+	for _, unit := range []struct {
+		flag  string
+		name  string
+		count int
+	}{{"lorem-words", "words", randomLoremWords}, {"sentences", "sentences", randomSentences}, {"paragraphs", "paragraphs", randomParagraphs}} {
+		if cmd.Flags().Changed(unit.flag) {
+			opts.unit, opts.count = unit.name, unit.count
+			set++
+		}
+	}
+	if set > 1 {
+		return loremOptions{}, fmt.Errorf("use only one of --lorem-words, --sentences, and --paragraphs")
+	}
+
+	switch strings.ToLower(randomLoremMode) {
+	case "classic":
+		opts.classic = true
+	case "random":
+	default:
+		return loremOptions{}, fmt.Errorf("unsupported lorem mode: %s (supported: classic, random)", randomLoremMode)
+	}
+	return opts, nil
+}
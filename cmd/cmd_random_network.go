@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hostRange is the usable addresses of one prefix: size addresses starting
+// first hosts after the network address.
+type hostRange struct {
+	prefix netip.Prefix
+	first  *big.Int
+	size   *big.Int
+}
+
+// usableHosts returns the addresses of prefix that can be assigned to
+// hosts. IPv4 networks lose their network and broadcast addresses, and IPv6
+// networks their Subnet-Router anycast address, except for point-to-point
+// prefixes (/31 and /127, RFC 3021 and RFC 6164) and single addresses.
+func usableHosts(prefix netip.Prefix) hostRange {
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+	r := hostRange{prefix: prefix, first: big.NewInt(0), size: size}
+	if hostBits >= 2 {
+		r.first = big.NewInt(1)
+		if prefix.Addr().Is4() {
+			size.Sub(size, big.NewInt(2))
+		} else {
+			size.Sub(size, big.NewInt(1))
+		}
+	}
+	return r
+}
+
+// newIPGenerator draws addresses uniformly from the usable hosts of one or
+// more CIDRs, so larger networks are picked proportionally more often.
+// outermostPrefixes drops prefixes that repeat or lie inside another one, so
+// no address is counted twice. Prefixes either nest or are disjoint, so what
+// is left does not overlap.
+func outermostPrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	var out []netip.Prefix
+	for i, p := range prefixes {
+		contained := false
+		for j, q := range prefixes {
+			if i != j && q.Bits() <= p.Bits() && q.Contains(p.Addr()) && (q.Bits() < p.Bits() || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func newIPGenerator(cidrs []string) (randomGenerator, error) {
+	if len(cidrs) == 0 {
+		return randomGenerator{}, fmt.Errorf("ip type requires --cidr, e.g. 10.0.0.0/8 or 2001:db8::/32")
+	}
+	var prefixes []netip.Prefix
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return randomGenerator{}, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	var ranges []hostRange
+	total := new(big.Int)
+	for _, prefix := range outermostPrefixes(prefixes) {
+		r := usableHosts(prefix)
+		ranges = append(ranges, r)
+		total.Add(total, r.size)
+	}
+
+	return randomGenerator{
+		name:   "IP address",
+		label:  fmt.Sprintf("Random IP in %s", strings.Join(cidrs, ", ")),
+		notes:  []string{fmt.Sprintf("Usable addresses: %s", total)},
+		column: "ip",
+		space:  total,
+		next: func() (string, error) {
+			n, err := randomInt(total)
+			if err != nil {
+				return "", err
+			}
+			for _, r := range ranges {
+				if n.Cmp(r.size) < 0 {
+					return offsetAddr(r.prefix.Addr(), n.Add(n, r.first)).String(), nil
+				}
+				n.Sub(n, r.size)
+			}
+			return "", fmt.Errorf("address index out of range")
+		},
+	}, nil
+}
+
+// offsetAddr returns base plus offset.
+func offsetAddr(base netip.Addr, offset *big.Int) netip.Addr {
+	sum := new(big.Int).SetBytes(base.AsSlice())
+	sum.Add(sum, offset)
+	b := sum.FillBytes(make([]byte, base.BitLen()/8))
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// newMACGenerator returns locally administered unicast MAC addresses, which
+// never collide with vendor-assigned ones.
+func newMACGenerator() randomGenerator {
+	return randomGenerator{
+		name:  "MAC address",
+		label: "Random MAC address (locally administered)",
+		space: new(big.Int).Lsh(big.NewInt(1), 46),
+		next: func() (string, error) {
+			b := make([]byte, 6)
+			if err := randomBytes(b); err != nil {
+				return "", err
+			}
+			// Set the locally administered bit and clear the multicast bit.
+			b[0] = b[0]&0xfc | 0x02
+			return net.HardwareAddr(b).String(), nil
+		},
+	}
+}
+
+// portRange is an inclusive range of ports.
+type portRange struct {
+	lo, hi int
+}
+
+// parsePortRanges parses ports and ranges such as "80", "8000-8999", merging
+// overlaps so every port is equally likely.
+func parsePortRanges(specs []string) ([]portRange, error) {
+	var ranges []portRange
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		loText, hiText, isRange := strings.Cut(spec, "-")
+		lo, err := strconv.Atoi(strings.TrimSpace(loText))
+		hi := lo
+		if err == nil && isRange {
+			hi, err = strconv.Atoi(strings.TrimSpace(hiText))
+		}
+		if err != nil || lo < 1 || hi > 65535 || lo > hi {
+			return nil, fmt.Errorf("invalid port range %q: use PORT or LOW-HIGH within 1-65535", spec)
+		}
+		ranges = append(ranges, portRange{lo, hi})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("port type requires --ports")
+	}
+
+	sort.Slice(ranges, func(a, b int) bool { return ranges[a].lo < ranges[b].lo })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.lo <= last.hi+1 {
+			last.hi = max(last.hi, r.hi)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
+
+// newPortGenerator draws ports uniformly from the given ranges.
+func newPortGenerator(specs []string) (randomGenerator, error) {
+	ranges, err := parsePortRanges(specs)
+	if err != nil {
+		return randomGenerator{}, err
+	}
+	total := 0
+	for _, r := range ranges {
+		total += r.hi - r.lo + 1
+	}
+
+	return randomGenerator{
+		name:    "port",
+		label:   fmt.Sprintf("Random port (%s)", strings.Join(specs, ", ")),
+		space:   big.NewInt(int64(total)),
+		numeric: true,
+		next: func() (string, error) {
+			n, err := randomIndex(total)
+			if err != nil {
+				return "", err
+			}
+			for _, r := range ranges {
+				if size := r.hi - r.lo + 1; n >= size {
+					n -= size
+					continue
+				}
+				return strconv.Itoa(r.lo + n), nil
+			}
+			return "", fmt.Errorf("port index out of range")
+		},
+	}, nil
+}
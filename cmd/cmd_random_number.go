@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxSampleAttempts bounds how often a distribution sample falling outside
+// --min/--max is redrawn before giving up.
+const maxSampleAttempts = 1000
+
+// newNumberGenerator builds the generator for --type number and --type
+// float. Uniform ranges are exact at any magnitude; the other distributions
+// sample a float64 and are redrawn while outside an explicitly given
+// --min/--max.
+func newNumberGenerator(cmd *cobra.Command, float bool) (randomGenerator, error) {
+	minVal, err := parseDecimal(randomMin)
+	if err != nil {
+		return randomGenerator{}, fmt.Errorf("invalid --min: %w", err)
+	}
+	maxVal, err := parseDecimal(randomMax)
+	if err != nil {
+		return randomGenerator{}, fmt.Errorf("invalid --max: %w", err)
+	}
+	if !float && (!minVal.IsInt() || !maxVal.IsInt()) {
+		return randomGenerator{}, fmt.Errorf("--min and --max must be integers for numbers (use --type float for decimals)")
+	}
+	if randomPrecision < 0 {
+		return randomGenerator{}, fmt.Errorf("precision cannot be negative")
+	}
+
+	kind, name := "number", "random number"
+	if float {
+		kind, name = "float", "random float"
+	}
+
+	dist := strings.ToLower(randomDistribution)
+	if dist == "uniform" {
+		if maxVal.Cmp(minVal) <= 0 {
+			return randomGenerator{}, fmt.Errorf("max value must be greater than min value")
+		}
+		if float {
+			return newUniformFloatGenerator(minVal, maxVal, randomPrecision)
+		}
+		lo, hi := minVal.Num(), maxVal.Num()
+		return randomGenerator{
+			name:    name,
+			label:   fmt.Sprintf("Random number (%s-%s)", lo, hi),
+			space:   new(big.Int).Sub(hi, lo),
+			numeric: true,
+			next: func() (string, error) {
+				n, err := generateRandomBigInt(lo, hi)
+				if err != nil {
+					return "", err
+				}
+				return n.String(), nil
+			},
+		}, nil
+	}
+
+	var sample func() (float64, error)
+	var params string
+	switch dist {
+	case "normal":
+		if randomStddev <= 0 {
+			return randomGenerator{}, fmt.Errorf("normal distribution requires a positive --stddev")
+		}
+		mean, stddev := randomMean, randomStddev
+		sample = func() (float64, error) { return sampleNormal(mean, stddev) }
+		params = fmt.Sprintf("normal, mean %g, stddev %g", mean, stddev)
+	case "exponential":
+		if randomMean <= 0 {
+			return randomGenerator{}, fmt.Errorf("exponential distribution requires a positive --mean")
+		}
+		mean := randomMean
+		sample = func() (float64, error) { return sampleExponential(mean) }
+		params = fmt.Sprintf("exponential, mean %g", mean)
+	case "poisson":
+		if float {
+			return randomGenerator{}, fmt.Errorf("poisson distribution produces integers; use --type number")
+		}
+		if randomMean <= 0 {
+			return randomGenerator{}, fmt.Errorf("poisson distribution requires a positive --mean")
+		}
+		mean := randomMean
+		sample = func() (float64, error) { return samplePoisson(mean) }
+		params = fmt.Sprintf("poisson, mean %g", mean)
+	default:
+		return randomGenerator{}, fmt.Errorf("unsupported distribution: %s (supported: uniform, normal, exponential, poisson)", randomDistribution)
+	}
+
+	// Only bounds the user asked for apply; the defaults would otherwise
+	// silently truncate most distributions.
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if cmd.Flags().Changed("min") {
+		lo, _ = minVal.Float64()
+	}
+	if cmd.Flags().Changed("max") {
+		hi, _ = maxVal.Float64()
+	}
+	if hi <= lo {
+		return randomGenerator{}, fmt.Errorf("max value must be greater than min value")
+	}
+
+	precision := randomPrecision
+	if !float {
+		precision = 0
+	}
+	return randomGenerator{
+		name:    name,
+		label:   fmt.Sprintf("Random %s (%s)", kind, params),
+		numeric: true,
+		next: func() (string, error) {
+			for i := 0; i < maxSampleAttempts; i++ {
+				x, err := sample()
+				if err != nil {
+					return "", err
+				}
+				x = roundTo(x, precision)
+				if x >= lo && x < hi {
+					return formatSample(x, precision), nil
+				}
+			}
+			return "", fmt.Errorf("no sample within [%s, %s) after %d attempts", randomMin, randomMax, maxSampleAttempts)
+		},
+	}, nil
+}
+
+// newUniformFloatGenerator draws uniformly from the decimals in [min, max)
+// that have precision fractional digits. Working on the scaled integer grid
+// keeps every representable value equally likely at any magnitude.
+func newUniformFloatGenerator(minVal, maxVal *big.Rat, precision int) (randomGenerator, error) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	lo := ceilRat(new(big.Rat).Mul(minVal, new(big.Rat).SetInt(scale)))
+	hi := ceilRat(new(big.Rat).Mul(maxVal, new(big.Rat).SetInt(scale)))
+	if hi.Cmp(lo) <= 0 {
+		return randomGenerator{}, fmt.Errorf("range contains no values with %d decimal places", precision)
+	}
+	return randomGenerator{
+		name:    "random float",
+		label:   fmt.Sprintf("Random float (%s-%s)", randomMin, randomMax),
+		space:   new(big.Int).Sub(hi, lo),
+		numeric: true,
+		next: func() (string, error) {
+			n, err := generateRandomBigInt(lo, hi)
+			if err != nil {
+				return "", err
+			}
+			return new(big.Rat).SetFrac(n, scale).FloatString(precision), nil
+		},
+	}, nil
+}
+
+// parseDecimal parses an integer, decimal, or scientific-notation value such
+// as "-9e18" exactly.
+func parseDecimal(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.ReplaceAll(strings.TrimSpace(s), "_", ""))
+	if !ok {
+		return nil, fmt.Errorf("not a number: %s", s)
+	}
+	return r, nil
+}
+
+// ceilRat returns the smallest integer not less than r.
+func ceilRat(r *big.Rat) *big.Int {
+	q, m := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// generateRandomBigInt returns a uniform random integer in [minVal, maxVal).
+func generateRandomBigInt(minVal, maxVal *big.Int) (*big.Int, error) {
+	n, err := randomInt(new(big.Int).Sub(maxVal, minVal))
+	if err != nil {
+		return nil, err
+	}
+	return n.Add(n, minVal), nil
+}
+
+func roundTo(x float64, precision int) float64 {
+	scale := math.Pow(10, float64(precision))
+	return math.Round(x*scale) / scale
+}
+
+// formatSample prints x with precision decimals, never as negative zero.
+func formatSample(x float64, precision int) string {
+	s := strconv.FormatFloat(x, 'f', precision, 64)
+	if strings.Trim(s, "-0.") == "" {
+		s = strings.TrimPrefix(s, "-")
+	}
+	return s
+}
+
+// sampleNormal draws from N(mean, stddev²) with the Box-Muller transform.
+func sampleNormal(mean, stddev float64) (float64, error) {
+	u1, err := randomFloat64()
+	if err != nil {
+		return 0, err
+	}
+	u2, err := randomFloat64()
+	if err != nil {
+		return 0, err
+	}
+	z := math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+	return mean + stddev*z, nil
+}
+
+// sampleExponential draws from the exponential distribution with the given
+// mean by inverting its CDF.
+func sampleExponential(mean float64) (float64, error) {
+	u, err := randomFloat64()
+	if err != nil {
+		return 0, err
+	}
+	return -mean * math.Log(u), nil
+}
+
+// samplePoisson draws from the Poisson distribution with the given mean.
+// Small means multiply uniforms (Knuth); larger ones use Hörmann's
+// transformed rejection (PTRS), which runs in constant expected time.
+func samplePoisson(mean float64) (float64, error) {
+	if mean < 10 {
+		limit := math.Exp(-mean)
+		p := 1.0
+		for k := 0; ; k++ {
+			u, err := randomFloat64()
+			if err != nil {
+				return 0, err
+			}
+			p *= u
+			if p <= limit {
+				return float64(k), nil
+			}
+		}
+	}
+
+	logMean := math.Log(mean)
+	b := 0.931 + 2.53*math.Sqrt(mean)
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u, err := randomFloat64()
+		if err != nil {
+			return 0, err
+		}
+		v, err := randomFloat64()
+		if err != nil {
+			return 0, err
+		}
+		u -= 0.5
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + mean + 0.43)
+		if us >= 0.07 && v <= vr {
+			return k, nil
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -mean+k*logMean-lg {
+			return k, nil
+		}
+	}
+}
+
+// weightedChoice is one "value:weight" entry of --weights.
+type weightedChoice struct {
+	value  string
+	weight float64
+}
+
+// parseWeights parses "value:weight" entries. The weight follows the last
+// colon, so values may themselves contain colons.
+func parseWeights(entries []string) ([]weightedChoice, error) {
+	var choices []weightedChoice
+	total := 0.0
+	for _, entry := range entries {
+		i := strings.LastIndex(entry, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid weight %q (expected value:weight)", entry)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(entry[i+1:]), 64)
+		if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return nil, fmt.Errorf("invalid weight %q: must be a non-negative number", entry)
+		}
+		choices = append(choices, weightedChoice{value: strings.TrimSpace(entry[:i]), weight: weight})
+		total += weight
+	}
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("weighted choice requires --weights (e.g. GET:70,POST:20,DELETE:10)")
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("weights must not all be zero")
+	}
+	return choices, nil
+}
+
+// newWeightedGenerator picks among the --weights values with probability
+// proportional to their weights.
+func newWeightedGenerator(entries []string) (randomGenerator, error) {
+	choices, err := parseWeights(entries)
+	if err != nil {
+		return randomGenerator{}, err
+	}
+
+	total := 0.0
+	possible := int64(0)
+	numeric := true
+	for _, c := range choices {
+		total += c.weight
+		if c.weight > 0 {
+			possible++
+		}
+		if !isJSONNumber(c.value) {
+			numeric = false
+		}
+	}
+
+	return randomGenerator{
+		name:    "weighted choice",
+		label:   "Weighted choice",
+		space:   big.NewInt(possible),
+		numeric: numeric,
+		next: func() (string, error) {
+			u, err := randomFloat64()
+			if err != nil {
+				return "", err
+			}
+			return pickWeighted(choices, u*total), nil
+		},
+	}, nil
+}
+
+// pickWeighted returns the choice whose cumulative weight interval contains
+// target.
+func pickWeighted(choices []weightedChoice, target float64) string {
+	last := ""
+	for _, c := range choices {
+		if c.weight <= 0 {
+			continue
+		}
+		if target < c.weight {
+			return c.value
+		}
+		target -= c.weight
+		last = c.value
+	}
+	// Floating-point rounding can leave target just past the final weight.
+	return last
+}
+
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	var n json.Number
+	return json.Unmarshal([]byte(s), &n) == nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// defaultMaxRepeat bounds unbounded repetition (*, +, {n,}) in patterns.
+const defaultMaxRepeat = 10
+
+// regexGenerator produces strings that match a regular expression by
+// walking its parsed syntax tree.
+type regexGenerator struct {
+	pattern   *regexp.Regexp
+	tree      *syntax.Regexp
+	maxRepeat int
+}
+
+// newRegexGenerator parses pattern with Go (RE2) syntax. Unbounded
+// repetitions produce at most maxRepeat extra copies.
+func newRegexGenerator(pattern string, maxRepeat int) (*regexGenerator, error) {
+	if maxRepeat < 0 {
+		return nil, fmt.Errorf("repeat limit cannot be negative")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	tree, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return &regexGenerator{pattern: re, tree: tree, maxRepeat: maxRepeat}, nil
+}
+
+// generate returns a string matching the pattern. Assertions such as \b are
+// not modelled while generating, so candidates are checked against the
+// compiled pattern and redrawn if they do not match.
+func (g *regexGenerator) generate() (string, error) {
+	for attempt := 0; attempt < 100; attempt++ {
+		var b strings.Builder
+		if err := g.walk(&b, g.tree); err != nil {
+			return "", err
+		}
+		if g.pattern.MatchString(b.String()) {
+			return b.String(), nil
+		}
+	}
+	return "", fmt.Errorf("could not generate a string matching %s", g.pattern)
+}
+
+func (g *regexGenerator) walk(b *strings.Builder, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return fmt.Errorf("pattern cannot match anything")
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 {
+				flip, err := randomIndex(2)
+				if err != nil {
+					return err
+				}
+				if flip == 1 {
+					r = unicode.SimpleFold(r)
+				}
+			}
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		r, err := pickFromClass(re.Rune)
+		if err != nil {
+			return err
+		}
+		b.WriteRune(r)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		n, err := randomIndex(0x7f - 0x20)
+		if err != nil {
+			return err
+		}
+		b.WriteRune(rune(0x20 + n))
+	case syntax.OpCapture:
+		return g.walk(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := g.walk(b, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		n, err := randomIndex(len(re.Sub))
+		if err != nil {
+			return err
+		}
+		return g.walk(b, re.Sub[n])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lo, hi = 0, -1
+		case syntax.OpPlus:
+			lo, hi = 1, -1
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		}
+		if hi < 0 {
+			hi = lo + g.maxRepeat
+		}
+		n, err := randomIndex(hi - lo + 1)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < lo+n; i++ {
+			if err := g.walk(b, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+	default:
+		// Empty matches and assertions (^, $, \A, \z, \b, \B) add nothing.
+	}
+	return nil
+}
+
+// pickFromClass picks a rune from the class ranges (pairs of lo, hi),
+// preferring printable ASCII so negated classes such as [^,] stay readable.
+func pickFromClass(ranges []rune) (rune, error) {
+	if printable := clipRanges(ranges, 0x20, 0x7e); len(printable) > 0 {
+		ranges = printable
+	}
+	total := 0
+	for i := 0; i < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("empty character class")
+	}
+	n, err := randomIndex(total)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n), nil
+		}
+		n -= size
+	}
+	return ranges[len(ranges)-1], nil
+}
+
+// clipRanges intersects class ranges with [lo, hi].
+func clipRanges(ranges []rune, lo, hi rune) []rune {
+	var out []rune
+	for i := 0; i < len(ranges); i += 2 {
+		a, b := max(ranges[i], lo), min(ranges[i+1], hi)
+		if a <= b {
+			out = append(out, a, b)
+		}
+	}
+	return out
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var randomShuffleCmd = &cobra.Command{
+	Use:   "shuffle [file...]",
+	Short: "Print input lines in random order",
+	Long: `Print the lines of the given files, or stdin, in a uniformly random order.
+
+With --echo the arguments themselves are shuffled. With --weight-column the
+order is weighted: lines with larger weights tend to come first.`,
+	RunE: runRandomShuffle,
+}
+
+var randomSampleCmd = &cobra.Command{
+	Use:   "sample [file...]",
+	Short: "Print a random sample of input lines",
+	Long: `Print --count lines chosen uniformly without replacement from the given
+files, or stdin.
+
+Input is streamed through a reservoir, so only the sample is kept in memory
+and arbitrarily large logs can be sampled. The sample is printed in random
+order, or in input order with --keep-order. With --weight-column each line
+is chosen with probability proportional to the number in that column
+(weighted reservoir sampling); lines with a weight of 0 are never chosen.`,
+	RunE: runRandomSample,
+}
+
+var randomPickCmd = &cobra.Command{
+	Use:   "pick [file...]",
+	Short: "Print one random input line",
+	Long: `Print one line chosen uniformly from the given files, or stdin.
+
+  plz random pick --echo alice bob carol
+  plz random pick --weight-column 2 reviewers.txt
+
+With --weight-column the line is chosen with probability proportional to
+the number in that column.`,
+	RunE: runRandomPick,
+}
+
+var (
+	shuffleSeed         string
+	shuffleEcho         bool
+	shuffleWeightColumn int
+	shuffleDelimiter    string
+	sampleCount         int
+	sampleKeepOrder     bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{randomShuffleCmd, randomSampleCmd, randomPickCmd} {
+		c.Flags().StringVar(&shuffleSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
+		c.Flags().BoolVarP(&shuffleEcho, "echo", "e", false, "Treat the arguments as the input lines")
+		c.Flags().IntVarP(&shuffleWeightColumn, "weight-column", "w", 0, "Weight lines by this 1-based column")
+		c.Flags().StringVarP(&shuffleDelimiter, "delimiter", "d", "", "Column delimiter for --weight-column (default: whitespace)")
+		randomCmd.AddCommand(c)
+	}
+	randomSampleCmd.Flags().IntVarP(&sampleCount, "count", "n", 10, "Number of lines to sample")
+	randomSampleCmd.Flags().BoolVar(&sampleKeepOrder, "keep-order", false, "Print the sample in input order")
+}
+
+func runRandomShuffle(cmd *cobra.Command, args []string) error {
+	return runLineSample(cmd, args, -1, false)
+}
+
+func runRandomSample(cmd *cobra.Command, args []string) error {
+	if sampleCount < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+	return runLineSample(cmd, args, sampleCount, sampleKeepOrder)
+}
+
+func runRandomPick(cmd *cobra.Command, args []string) error {
+	return runLineSample(cmd, args, 1, false)
+}
+
+// runLineSample prints k lines sampled from the input, or all of them in
+// random order when k is negative.
+func runLineSample(cmd *cobra.Command, args []string, k int, keepOrder bool) error {
+	if shuffleWeightColumn < 0 {
+		return fmt.Errorf("weight column must be at least 1")
+	}
+	if shuffleSeed != "" {
+		useSeededSource(shuffleSeed)
+		defer useCryptoSource()
+		fmt.Fprintln(os.Stderr, seedWarning)
+	}
+
+	sampler := newLineSampler(k, shuffleWeightColumn, shuffleDelimiter)
+	if err := readLines(cmd, args, shuffleEcho, sampler.add); err != nil {
+		return err
+	}
+	lines, err := sampler.result(keepOrder)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 && sampler.weighted() && sampler.seen > 0 {
+		return fmt.Errorf("no line has a positive weight")
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// readLines calls fn for every line of the files in args, or of stdin when
+// there are none (or one is "-"). With echo the args are the lines.
+func readLines(cmd *cobra.Command, args []string, echo bool, fn func(string) error) error {
+	if echo {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+	for _, name := range args {
+		if name == "-" {
+			if err := scanLines(cmd.InOrStdin(), name, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		err = scanLines(f, name, fn)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanLines calls fn for each line of r, which is read as name.
+func scanLines(r io.Reader, name string, fn func(string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := fn(strings.TrimSuffix(scanner.Text(), "\r")); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// sampledLine is a line kept by a lineSampler, with its input position and,
+// for weighted sampling, its random key.
+type sampledLine struct {
+	line  string
+	index int
+	key   float64
+}
+
+// lineSampler keeps a uniform sample of up to k lines (Algorithm R), or a
+// weighted one (Efraimidis-Spirakis A-Res) when a weight column is set.
+// A negative k keeps every line.
+type lineSampler struct {
+	k         int
+	column    int
+	delimiter string
+	seen      int
+	kept      sampledHeap
+}
+
+func newLineSampler(k, column int, delimiter string) *lineSampler {
+	return &lineSampler{k: k, column: column, delimiter: delimiter}
+}
+
+func (s *lineSampler) weighted() bool {
+	return s.column > 0
+}
+
+func (s *lineSampler) add(line string) error {
+	index := s.seen
+	s.seen++
+	if s.weighted() {
+		return s.addWeighted(line, index)
+	}
+	if s.k < 0 || len(s.kept) < s.k {
+		s.kept = append(s.kept, sampledLine{line: line, index: index})
+		return nil
+	}
+	j, err := randomIndex(index + 1)
+	if err != nil {
+		return err
+	}
+	if j < s.k {
+		s.kept[j] = sampledLine{line: line, index: index}
+	}
+	return nil
+}
+
+// addWeighted gives the line the key log(u)/weight for uniform u and keeps
+// the k largest keys, which samples without replacement in proportion to
+// the weights.
+func (s *lineSampler) addWeighted(line string, index int) error {
+	weight, err := s.weight(line)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", index+1, err)
+	}
+	if weight == 0 {
+		return nil
+	}
+	u, err := randomFloat64()
+	if err != nil {
+		return err
+	}
+	item := sampledLine{line: line, index: index, key: math.Log(u) / weight}
+	switch {
+	case s.k < 0 || len(s.kept) < s.k:
+		heap.Push(&s.kept, item)
+	case item.key > s.kept[0].key:
+		s.kept[0] = item
+		heap.Fix(&s.kept, 0)
+	}
+	return nil
+}
+
+func (s *lineSampler) weight(line string) (float64, error) {
+	var fields []string
+	if s.delimiter == "" {
+		fields = strings.Fields(line)
+	} else {
+		fields = strings.Split(line, s.delimiter)
+	}
+	if s.column > len(fields) {
+		return 0, fmt.Errorf("no column %d", s.column)
+	}
+	field := strings.TrimSpace(fields[s.column-1])
+	weight, err := strconv.ParseFloat(field, 64)
+	if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return 0, fmt.Errorf("invalid weight %q", field)
+	}
+	return weight, nil
+}
+
+// result returns the kept lines in input order, or in random order: a
+// shuffle for uniform samples, and by descending key for weighted ones.
+func (s *lineSampler) result(keepOrder bool) ([]string, error) {
+	kept := append([]sampledLine(nil), s.kept...)
+	switch {
+	case keepOrder:
+		sort.Slice(kept, func(a, b int) bool { return kept[a].index < kept[b].index })
+	case s.weighted():
+		sort.Slice(kept, func(a, b int) bool { return kept[a].key > kept[b].key })
+	default:
+		for i := len(kept) - 1; i > 0; i-- {
+			j, err := randomIndex(i + 1)
+			if err != nil {
+				return nil, err
+			}
+			kept[i], kept[j] = kept[j], kept[i]
+		}
+	}
+	lines := make([]string, len(kept))
+	for i, item := range kept {
+		lines[i] = item.line
+	}
+	return lines, nil
+}
+
+// sampledHeap is a min-heap of lines by key.
+type sampledHeap []sampledLine
+
+func (h sampledHeap) Len() int            { return len(h) }
+func (h sampledHeap) Less(a, b int) bool  { return h[a].key < h[b].key }
+func (h sampledHeap) Swap(a, b int)       { h[a], h[b] = h[b], h[a] }
+func (h *sampledHeap) Push(x interface{}) { *h = append(*h, x.(sampledLine)) }
+func (h *sampledHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
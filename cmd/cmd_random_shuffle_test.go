@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLineSampler(t *testing.T) {
+	useSeededSource("sampler")
+	defer useCryptoSource()
+
+	sample := func(k, column int, keepOrder bool, lines ...string) []string {
+		t.Helper()
+		s := newLineSampler(k, column, "")
+		for _, line := range lines {
+			if err := s.add(line); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		out, err := s.result(keepOrder)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return out
+	}
+
+	var input []string
+	for i := 0; i < 50; i++ {
+		input = append(input, strconv.Itoa(i))
+	}
+
+	shuffled := sample(-1, 0, false, input...)
+	sorted := append([]string(nil), shuffled...)
+	sort.Slice(sorted, func(a, b int) bool {
+		x, _ := strconv.Atoi(sorted[a])
+		y, _ := strconv.Atoi(sorted[b])
+		return x < y
+	})
+	if strings.Join(sorted, ",") != strings.Join(input, ",") {
+		t.Errorf("Shuffle lost or duplicated lines: %v", shuffled)
+	}
+	if strings.Join(shuffled, ",") == strings.Join(input, ",") {
+		t.Error("Shuffle kept the input order")
+	}
+
+	kept := sample(5, 0, true, input...)
+	if len(kept) != 5 {
+		t.Fatalf("Expected 5 lines, got %v", kept)
+	}
+	for i := 1; i < len(kept); i++ {
+		x, _ := strconv.Atoi(kept[i-1])
+		y, _ := strconv.Atoi(kept[i])
+		if x >= y {
+			t.Errorf("Expected input order with --keep-order, got %v", kept)
+		}
+	}
+
+	if got := sample(10, 0, false, "a", "b"); len(got) != 2 {
+		t.Errorf("Expected every line when the sample exceeds the input, got %v", got)
+	}
+
+	// Every line should be equally likely to be sampled.
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		for _, line := range sample(2, 0, false, "a", "b", "c", "d") {
+			counts[line]++
+		}
+	}
+	for _, line := range []string{"a", "b", "c", "d"} {
+		if counts[line] < 1800 || counts[line] > 2200 {
+			t.Errorf("Line %s sampled %d times, expected about 2000", line, counts[line])
+		}
+	}
+
+	// Weighted picks follow the weights, and zero weights are never picked.
+	counts = make(map[string]int)
+	for i := 0; i < 9000; i++ {
+		counts[sample(1, 2, false, "alice 1", "bob 0", "carol 8")[0]]++
+	}
+	if counts["bob 0"] != 0 {
+		t.Errorf("Zero-weight line picked %d times", counts["bob 0"])
+	}
+	if counts["alice 1"] < 800 || counts["alice 1"] > 1200 {
+		t.Errorf("Weight 1 of 9 picked %d times, expected about 1000", counts["alice 1"])
+	}
+}
+
+func TestLineSamplerWeights(t *testing.T) {
+	tests := []struct {
+		line      string
+		delimiter string
+		want      float64
+		wantErr   bool
+	}{
+		{"alice 2.5", "", 2.5, false},
+		{"alice,3", ",", 3, false},
+		{"alice, 3 ", ",", 3, false},
+		{"alice", "", 0, true},
+		{"alice -1", "", 0, true},
+		{"alice many", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := newLineSampler(1, 2, tt.delimiter).weight(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got weight %g", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected weight %g, got %g (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math/big"
+	mathrand "math/rand/v2"
+)
+
+// randomSource supplies the randomness behind every generator. It is the
+// operating system CSPRNG unless a seed selects a deterministic stream.
+var randomSource io.Reader = rand.Reader
+
+// randomSeeded reports whether randomSource is the deterministic stream.
+var randomSeeded bool
+
+// seedWarning marks output produced from a seeded source.
+const seedWarning = "Seeded: deterministic output, NOT cryptographically secure"
+
+// useSeededSource switches generators to a ChaCha8 stream keyed by the
+// SHA-256 of seed, so the same seed always yields the same values.
+func useSeededSource(seed string) {
+	randomSource = mathrand.NewChaCha8(sha256.Sum256([]byte(seed)))
+	randomSeeded = true
+}
+
+// useCryptoSource restores the operating system CSPRNG.
+func useCryptoSource() {
+	randomSource = rand.Reader
+	randomSeeded = false
+}
+
+// randomBytes fills b from randomSource.
+func randomBytes(b []byte) error {
+	_, err := io.ReadFull(randomSource, b)
+	return err
+}
+
+// randomInt returns a uniform random integer in [0, n).
+func randomInt(n *big.Int) (*big.Int, error) {
+	return rand.Int(randomSource, n)
+}
+
+// randomIndex returns a uniform random integer in [0, n).
+func randomIndex(n int) (int, error) {
+	v, err := randomInt(big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+// randomFloat64 returns a uniform random float64 in the open interval
+// (0, 1), so callers can safely take its logarithm.
+func randomFloat64() (float64, error) {
+	v, err := randomInt(big.NewInt(1 << 53))
+	if err != nil {
+		return 0, err
+	}
+	return (float64(v.Int64()) + 0.5) / (1 << 53), nil
+}
@@ -0,0 +1,603 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var randomStrengthCmd = &cobra.Command{
+	Use:   "strength [password]",
+	Short: "Estimate how guessable a password is",
+	Long: `Estimate the strength of a password in the style of zxcvbn.
+
+The password is split into the patterns an attacker would try: common
+passwords, English words, and names from embedded frequency lists (also
+reversed or with l33t substitutions such as @ for a), keyboard walks on
+QWERTY and the keypad, repeats, sequences, years, and dates. The cheapest
+combination of patterns gives the estimated number of guesses, a score from
+0 (too guessable) to 4 (very unguessable), crack times for four attack
+scenarios, and feedback on how to improve the password.
+
+--user-input adds words an attacker would try first, such as the user's
+name, email, or the site name.
+
+Only the first 100 characters are analyzed. The password is read from stdin
+when no argument (or "-") is given, which keeps it out of the shell history.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRandomStrength,
+}
+
+var strengthUserInputs []string
+
+// maxStrengthLength bounds the analysis, which is quadratic in the length.
+const maxStrengthLength = 100
+
+func init() {
+	randomStrengthCmd.Flags().StringSliceVar(&strengthUserInputs, "user-input", nil, "Words related to the user to penalize (names, emails, site)")
+	randomCmd.AddCommand(randomStrengthCmd)
+}
+
+func runRandomStrength(cmd *cobra.Command, args []string) error {
+	var password string
+	if len(args) == 0 || args[0] == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	} else {
+		password = args[0]
+	}
+
+	dicts := loadStrengthDictionaries()
+	if len(strengthUserInputs) > 0 {
+		dicts = withUserInputs(dicts, strengthUserInputs)
+	}
+	runes := []rune(password)
+	if len(runes) > maxStrengthLength {
+		runes = runes[:maxStrengthLength]
+	}
+	result := estimateStrength(runes, dicts)
+
+	fmt.Printf("Score: %d/4 (%s)\n", result.score, strengthScoreNames[result.score])
+	fmt.Printf("Guesses: %.4g (10^%.2f)\n", result.guesses, math.Log10(result.guesses))
+	fmt.Println("Crack time:")
+	for _, scenario := range crackScenarios {
+		fmt.Printf("  %s: %s\n", scenario.name, displayCrackTime(result.guesses/scenario.perSecond))
+	}
+	if len(result.sequence) > 0 {
+		fmt.Println("Patterns:")
+		for _, m := range result.sequence {
+			fmt.Printf("  %s\n", describeMatch(m))
+		}
+	}
+	if result.warning != "" {
+		fmt.Printf("Warning: %s\n", result.warning)
+	}
+	if len(result.suggestions) > 0 {
+		fmt.Println("Suggestions:")
+		for _, s := range result.suggestions {
+			fmt.Printf("  - %s\n", s)
+		}
+	}
+	return nil
+}
+
+// withUserInputs returns dicts plus a "user_inputs" dictionary. Each input
+// is also split into words so "jane.doe@example.com" penalizes "jane".
+func withUserInputs(dicts map[string]map[string]int, inputs []string) map[string]map[string]int {
+	var words []string
+	for _, input := range inputs {
+		words = append(words, input)
+		words = append(words, strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	extended := make(map[string]map[string]int, len(dicts)+1)
+	for name, dict := range dicts {
+		extended[name] = dict
+	}
+	extended["user_inputs"] = rankedDictionary(words)
+	return extended
+}
+
+var strengthScoreNames = []string{
+	"too guessable", "very guessable", "somewhat guessable", "safely unguessable", "very unguessable",
+}
+
+// crackScenarios are the attack rates zxcvbn reports: a rate-limited and an
+// unlimited online attack, and offline attacks on a slow (bcrypt, scrypt)
+// and a fast (unsalted SHA-1) hash.
+var crackScenarios = []struct {
+	name      string
+	perSecond float64
+}{
+	{"online, throttled (100/hour)", 100.0 / 3600},
+	{"online, unthrottled (10/second)", 10},
+	{"offline, slow hash (1e4/second)", 1e4},
+	{"offline, fast hash (1e10/second)", 1e10},
+}
+
+// strengthResult is the outcome of estimateStrength.
+type strengthResult struct {
+	guesses     float64
+	score       int
+	sequence    []*strengthMatch
+	warning     string
+	suggestions []string
+}
+
+// Constants from zxcvbn's scoring model.
+const (
+	bruteforceCardinality  = 10
+	minSubmatchGuesses     = 10
+	minMultiSubmatchGuess  = 50
+	minGuessesBeforeGrowth = 10000
+	minYearSpace           = 20
+)
+
+// referenceYear anchors date and year guesses; recent years are guessed
+// first.
+var referenceYear = func() int { return time.Now().Year() }
+
+// estimateStrength finds the sequence of non-overlapping matches (with
+// brute force filling the gaps) that needs the fewest guesses in total.
+// A sequence of l matches costs l! times the product of their guesses, plus
+// a penalty that grows with l so long chains of tiny matches do not win.
+func estimateStrength(pw []rune, dicts map[string]map[string]int) strengthResult {
+	n := len(pw)
+	if n == 0 {
+		return strengthResult{guesses: 1, suggestions: defaultStrengthSuggestions}
+	}
+
+	byEnd := make([][]*strengthMatch, n)
+	for _, m := range strengthMatches(pw, dicts) {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	type candidate struct {
+		m     *strengthMatch
+		pi, g float64
+	}
+	optimal := make([]map[int]candidate, n)
+	for k := range optimal {
+		optimal[k] = make(map[int]candidate)
+	}
+
+	update := func(m *strengthMatch, l int) {
+		k := m.j
+		pi := matchGuesses(m, n)
+		if l > 1 {
+			pi *= optimal[m.i-1][l-1].pi
+		}
+		g := factorial(l)*pi + math.Pow(minGuessesBeforeGrowth, float64(l-1))
+		for cl, c := range optimal[k] {
+			if cl <= l && c.g <= g {
+				return
+			}
+		}
+		optimal[k][l] = candidate{m, pi, g}
+	}
+	bruteforce := func(i, j int) *strengthMatch {
+		return &strengthMatch{pattern: "bruteforce", i: i, j: j, token: string(pw[i : j+1])}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, m := range byEnd[k] {
+			if m.i == 0 {
+				update(m, 1)
+				continue
+			}
+			for _, l := range sortedLengths(optimal[m.i-1]) {
+				update(m, l+1)
+			}
+		}
+
+		update(bruteforce(0, k), 1)
+		for i := 1; i <= k; i++ {
+			m := bruteforce(i, k)
+			for _, l := range sortedLengths(optimal[i-1]) {
+				if optimal[i-1][l].m.pattern != "bruteforce" {
+					update(m, l+1)
+				}
+			}
+		}
+	}
+
+	best := -1
+	for _, l := range sortedLengths(optimal[n-1]) {
+		if best < 0 || optimal[n-1][l].g < optimal[n-1][best].g {
+			best = l
+		}
+	}
+	result := strengthResult{guesses: optimal[n-1][best].g}
+	for k, l := n-1, best; k >= 0; l-- {
+		m := optimal[k][l].m
+		result.sequence = append([]*strengthMatch{m}, result.sequence...)
+		k = m.i - 1
+	}
+	result.score = guessesToScore(result.guesses)
+	result.warning, result.suggestions = strengthFeedback(result)
+	return result
+}
+
+func sortedLengths[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for l := range m {
+		keys = append(keys, l)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// matchGuesses estimates, and caches, the guesses needed for one match. A
+// match that is only part of the password needs at least a few guesses so
+// that splitting into tiny pieces is never free.
+func matchGuesses(m *strengthMatch, passwordLen int) float64 {
+	if m.guesses != 0 {
+		return m.guesses
+	}
+	length := len([]rune(m.token))
+	minGuesses := 1.0
+	if length < passwordLen {
+		minGuesses = minMultiSubmatchGuess
+		if length == 1 {
+			minGuesses = minSubmatchGuesses
+		}
+	}
+
+	var guesses float64
+	switch m.pattern {
+	case "bruteforce":
+		guesses = math.Min(math.Pow(bruteforceCardinality, float64(length)), math.MaxFloat64)
+		floor := float64(minMultiSubmatchGuess + 1)
+		if length == 1 {
+			floor = minSubmatchGuesses + 1
+		}
+		guesses = math.Max(guesses, floor)
+	case "dictionary":
+		guesses = float64(m.rank) * uppercaseVariations(m.token) * l33tVariations(m)
+		if m.reversed {
+			guesses *= 2
+		}
+	case "spatial":
+		guesses = spatialGuesses(m)
+	case "repeat":
+		guesses = m.baseGuesses * float64(m.repeats)
+	case "sequence":
+		switch first := []rune(m.token)[0]; {
+		case strings.ContainsRune("aAzZ019", first):
+			guesses = 4
+		case unicode.IsDigit(first):
+			guesses = 10
+		default:
+			guesses = 26
+		}
+		if !m.ascending {
+			guesses *= 2
+		}
+		guesses *= float64(length)
+	case "year":
+		guesses = float64(max(abs(m.year-referenceYear()), minYearSpace))
+	case "date":
+		guesses = float64(max(abs(m.year-referenceYear()), minYearSpace)) * 365
+		if m.separator != "" {
+			guesses *= 4
+		}
+	}
+	m.guesses = math.Max(guesses, minGuesses)
+	return m.guesses
+}
+
+// uppercaseVariations counts the ways the capitals in a word could have
+// been placed. Capitalizing only the first or last letter, or every
+// letter, is so common that it merely doubles the guesses.
+func uppercaseVariations(token string) float64 {
+	upper, lower := 0, 0
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	runes := []rune(token)
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(runes[0]) || unicode.IsUpper(runes[len(runes)-1]))) {
+		return 2
+	}
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+// l33tVariations counts the ways the substituted characters could have
+// been mixed with the letters they replace.
+func l33tVariations(m *strengthMatch) float64 {
+	if !m.l33t {
+		return 1
+	}
+	variations := 1.0
+	lower := lowerRunes([]rune(m.token))
+	for sub, letter := range m.subs {
+		subbed, unsubbed := 0, 0
+		for _, r := range lower {
+			switch r {
+			case sub:
+				subbed++
+			case letter:
+				unsubbed++
+			}
+		}
+		if subbed == 0 || unsubbed == 0 {
+			variations *= 2
+			continue
+		}
+		possibilities := 0.0
+		for i := 1; i <= min(subbed, unsubbed); i++ {
+			possibilities += binomial(subbed+unsubbed, i)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+// spatialGuesses counts the keyboard walks of the same length with at most
+// as many turns, starting from any key, then accounts for shifted keys.
+func spatialGuesses(m *strengthMatch) float64 {
+	var g keyboardGraph
+	for _, candidate := range keyboardGraphs() {
+		if candidate.name == m.graph {
+			g = candidate
+		}
+	}
+	starts := float64(len(g.neighbors))
+	length := len([]rune(m.token))
+
+	guesses := 0.0
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= min(m.turns, i-1); j++ {
+			guesses += binomial(i-1, j-1) * starts * math.Pow(g.degree, float64(j))
+		}
+	}
+	if m.shifted > 0 {
+		unshifted := length - m.shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			variations := 0.0
+			for i := 1; i <= min(m.shifted, unshifted); i++ {
+				variations += binomial(m.shifted+unshifted, i)
+			}
+			guesses *= variations
+		}
+	}
+	return guesses
+}
+
+func binomial(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+	r := 1.0
+	for d := 1; d <= k; d++ {
+		r = r * float64(n-d+1) / float64(d)
+	}
+	return r
+}
+
+func factorial(n int) float64 {
+	r := 1.0
+	for i := 2; i <= n; i++ {
+		r *= float64(i)
+	}
+	return r
+}
+
+// guessesToScore maps guesses to the zxcvbn 0-4 score. The small delta
+// keeps passwords sitting exactly on a threshold in the lower bucket.
+func guessesToScore(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// displayCrackTime rounds seconds to the largest whole unit.
+func displayCrackTime(seconds float64) string {
+	const (
+		minute  = 60
+		hour    = minute * 60
+		day     = hour * 24
+		month   = day * 31
+		year    = month * 12
+		century = year * 100
+	)
+	units := []struct {
+		name string
+		size float64
+	}{{"second", 1}, {"minute", minute}, {"hour", hour}, {"day", day}, {"month", month}, {"year", year}}
+
+	if seconds < 1 {
+		return "less than a second"
+	}
+	if seconds >= century {
+		return "centuries"
+	}
+	unit := units[0]
+	for _, u := range units {
+		if seconds >= u.size {
+			unit = u
+		}
+	}
+	n := math.Round(seconds / unit.size)
+	if n == 1 {
+		return "1 " + unit.name
+	}
+	return fmt.Sprintf("%.0f %ss", n, unit.name)
+}
+
+// describeMatch summarizes a match for the pattern list.
+func describeMatch(m *strengthMatch) string {
+	detail := ""
+	switch m.pattern {
+	case "dictionary":
+		detail = fmt.Sprintf("%s #%d", strings.ReplaceAll(m.dictionary, "_", " "), m.rank)
+		if m.word != strings.ToLower(m.token) {
+			detail += fmt.Sprintf(", %q", m.word)
+		}
+		if m.reversed {
+			detail += ", reversed"
+		}
+		if m.l33t {
+			var subs []string
+			for sub, letter := range m.subs {
+				subs = append(subs, fmt.Sprintf("%c->%c", sub, letter))
+			}
+			sort.Strings(subs)
+			detail += ", l33t " + strings.Join(subs, " ")
+		}
+	case "spatial":
+		detail = fmt.Sprintf("%s, %d turns", m.graph, m.turns)
+		if m.shifted > 0 {
+			detail += fmt.Sprintf(", %d shifted", m.shifted)
+		}
+	case "repeat":
+		detail = fmt.Sprintf("%q x%d", m.base, m.repeats)
+	case "sequence":
+		detail = "descending"
+		if m.ascending {
+			detail = "ascending"
+		}
+	case "year":
+		detail = fmt.Sprint(m.year)
+	case "date":
+		detail = fmt.Sprintf("%04d-%02d-%02d", m.year, m.month, m.day)
+	}
+
+	line := fmt.Sprintf("%-10s %q", m.pattern, m.token)
+	if detail != "" {
+		line += " (" + detail + ")"
+	}
+	return line + fmt.Sprintf(": 10^%.2f guesses", math.Log10(m.guesses))
+}
+
+var defaultStrengthSuggestions = []string{
+	"Use a few words, avoid common phrases",
+	"No need for symbols, digits, or uppercase letters",
+}
+
+// strengthFeedback explains the weakest part of a password scoring below
+// 3, judged by its longest match.
+func strengthFeedback(result strengthResult) (string, []string) {
+	if len(result.sequence) == 0 {
+		return "", defaultStrengthSuggestions
+	}
+	if result.score > 2 {
+		return "", nil
+	}
+
+	longest := result.sequence[0]
+	for _, m := range result.sequence[1:] {
+		if len([]rune(m.token)) > len([]rune(longest.token)) {
+			longest = m
+		}
+	}
+
+	suggestions := []string{"Add another word or two. Uncommon words are better."}
+	warning := ""
+	switch longest.pattern {
+	case "dictionary":
+		var extra []string
+		warning, extra = dictionaryFeedback(longest, len(result.sequence) == 1)
+		suggestions = append(suggestions, extra...)
+	case "spatial":
+		warning = "Short keyboard patterns are easy to guess"
+		if longest.turns == 1 {
+			warning = "Straight rows of keys are easy to guess"
+		}
+		suggestions = append(suggestions, "Use a longer keyboard pattern with more turns")
+	case "repeat":
+		warning = `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		if len([]rune(longest.base)) == 1 {
+			warning = `Repeats like "aaa" are easy to guess`
+		}
+		suggestions = append(suggestions, "Avoid repeated words and characters")
+	case "sequence":
+		warning = "Sequences like abc or 6543 are easy to guess"
+		suggestions = append(suggestions, "Avoid sequences")
+	case "year":
+		warning = "Recent years are easy to guess"
+		suggestions = append(suggestions, "Avoid recent years", "Avoid years that are associated with you")
+	case "date":
+		warning = "Dates are often easy to guess"
+		suggestions = append(suggestions, "Avoid dates and years that are associated with you")
+	}
+	return warning, suggestions
+}
+
+func dictionaryFeedback(m *strengthMatch, sole bool) (string, []string) {
+	warning := ""
+	switch m.dictionary {
+	case "passwords":
+		switch {
+		case sole && !m.l33t && !m.reversed && m.rank <= 10:
+			warning = "This is a top-10 common password"
+		case sole && !m.l33t && !m.reversed && m.rank <= 100:
+			warning = "This is a top-100 common password"
+		case sole && !m.l33t && !m.reversed:
+			warning = "This is a very common password"
+		case math.Log10(m.guesses) <= 4:
+			warning = "This is similar to a commonly used password"
+		}
+	case "english":
+		if sole {
+			warning = "A word by itself is easy to guess"
+		}
+	case "surnames", "male_names", "female_names":
+		warning = "Common names and surnames are easy to guess"
+		if sole {
+			warning = "Names and surnames by themselves are easy to guess"
+		}
+	case "user_inputs":
+		warning = "Words related to you or this site are easy to guess"
+	}
+
+	var suggestions []string
+	runes := []rune(m.token)
+	lower := strings.ToLower(m.token)
+	switch {
+	case unicode.IsUpper(runes[0]) && strings.ToLower(string(runes[1:])) == string(runes[1:]):
+		suggestions = append(suggestions, "Capitalization doesn't help very much")
+	case strings.ToUpper(m.token) == m.token && lower != m.token:
+		suggestions = append(suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+	}
+	if m.reversed && len(runes) >= 4 {
+		suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+	}
+	if m.l33t {
+		suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+	}
+	return warning, suggestions
+}
@@ -0,0 +1,608 @@
+package cmd
+
+import (
+	"embed"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// strengthData holds frequency lists trimmed from zxcvbn-go (MIT licensed,
+// see data/strength/LICENSE), one lowercase word per line, most common first.
+//
+//go:embed data/strength/*.txt
+var strengthData embed.FS
+
+// strengthDictionaryNames lists the embedded dictionaries in match order.
+var strengthDictionaryNames = []string{"passwords", "english", "female_names", "male_names", "surnames"}
+
+// loadStrengthDictionaries maps each dictionary name to word -> rank, where
+// rank 1 is the most common word.
+var loadStrengthDictionaries = sync.OnceValue(func() map[string]map[string]int {
+	dicts := make(map[string]map[string]int)
+	for _, name := range strengthDictionaryNames {
+		data, err := strengthData.ReadFile(path.Join("data/strength", name+".txt"))
+		if err != nil {
+			panic(err)
+		}
+		dicts[name] = rankedDictionary(strings.Split(string(data), "\n"))
+	}
+	return dicts
+})
+
+func rankedDictionary(words []string) map[string]int {
+	ranks := make(map[string]int)
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if _, ok := ranks[word]; word != "" && !ok {
+			ranks[word] = len(ranks) + 1
+		}
+	}
+	return ranks
+}
+
+// strengthMatch is a guessable pattern covering runes i..j (inclusive) of
+// a password. Only the fields for its pattern are set.
+type strengthMatch struct {
+	pattern string
+	i, j    int
+	token   string
+	guesses float64
+
+	// dictionary
+	word       string
+	rank       int
+	dictionary string
+	reversed   bool
+	l33t       bool
+	subs       map[rune]rune
+
+	// spatial
+	graph   string
+	turns   int
+	shifted int
+
+	// repeat
+	base        string
+	baseGuesses float64
+	repeats     int
+
+	// sequence
+	ascending bool
+
+	// date and year
+	year, month, day int
+	separator        string
+}
+
+// strengthMatches runs every matcher over the password and returns the
+// matches ordered by position.
+func strengthMatches(pw []rune, dicts map[string]map[string]int) []*strengthMatch {
+	var matches []*strengthMatch
+	matches = append(matches, dictionaryMatches(pw, dicts)...)
+	matches = append(matches, reversedDictionaryMatches(pw, dicts)...)
+	matches = append(matches, l33tMatches(pw, dicts)...)
+	for _, g := range keyboardGraphs() {
+		matches = append(matches, spatialMatches(pw, g)...)
+	}
+	matches = append(matches, repeatMatches(pw, dicts)...)
+	matches = append(matches, sequenceMatches(pw)...)
+	matches = append(matches, yearMatches(pw)...)
+	matches = append(matches, dateMatches(pw)...)
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].i != matches[b].i {
+			return matches[a].i < matches[b].i
+		}
+		return matches[a].j < matches[b].j
+	})
+	return matches
+}
+
+// lowerRunes lowercases rune by rune so indices stay aligned with pw.
+func lowerRunes(pw []rune) []rune {
+	lower := make([]rune, len(pw))
+	for i, r := range pw {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+func dictionaryMatches(pw []rune, dicts map[string]map[string]int) []*strengthMatch {
+	lower := lowerRunes(pw)
+	names := make([]string, 0, len(dicts))
+	for name := range dicts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var matches []*strengthMatch
+	for _, name := range names {
+		for i := range lower {
+			for j := i; j < len(lower); j++ {
+				word := string(lower[i : j+1])
+				if rank, ok := dicts[name][word]; ok {
+					matches = append(matches, &strengthMatch{
+						pattern: "dictionary", i: i, j: j, token: string(pw[i : j+1]),
+						word: word, rank: rank, dictionary: name,
+					})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func reversedDictionaryMatches(pw []rune, dicts map[string]map[string]int) []*strengthMatch {
+	reversed := make([]rune, len(pw))
+	for i, r := range pw {
+		reversed[len(pw)-1-i] = r
+	}
+	matches := dictionaryMatches(reversed, dicts)
+	for _, m := range matches {
+		m.i, m.j = len(pw)-1-m.j, len(pw)-1-m.i
+		m.token = string(pw[m.i : m.j+1])
+		m.reversed = true
+	}
+	return matches
+}
+
+// l33tTable lists the common substitutions for each letter.
+var l33tTable = map[rune][]rune{
+	'a': {'4', '@'},
+	'b': {'8'},
+	'c': {'(', '{', '[', '<'},
+	'e': {'3'},
+	'g': {'6', '9'},
+	'i': {'1', '!', '|'},
+	'l': {'1', '|', '7'},
+	'o': {'0'},
+	's': {'$', '5'},
+	't': {'+', '7'},
+	'x': {'%'},
+	'z': {'2'},
+}
+
+// l33tSubstitutions returns every way of reading the substitution
+// characters in pw as letters, e.g. "1" as either "i" or "l".
+func l33tSubstitutions(pw []rune) []map[rune]rune {
+	candidates := make(map[rune][]rune)
+	for letter, subs := range l33tTable {
+		for _, sub := range subs {
+			for _, r := range pw {
+				if r == sub {
+					candidates[sub] = append(candidates[sub], letter)
+					break
+				}
+			}
+		}
+	}
+	chars := make([]rune, 0, len(candidates))
+	for sub := range candidates {
+		chars = append(chars, sub)
+		sort.Slice(candidates[sub], func(a, b int) bool { return candidates[sub][a] < candidates[sub][b] })
+	}
+	sort.Slice(chars, func(a, b int) bool { return chars[a] < chars[b] })
+
+	tables := []map[rune]rune{{}}
+	for _, sub := range chars {
+		var next []map[rune]rune
+		for _, table := range tables {
+			for _, letter := range candidates[sub] {
+				extended := map[rune]rune{sub: letter}
+				for k, v := range table {
+					extended[k] = v
+				}
+				next = append(next, extended)
+			}
+		}
+		tables = next
+	}
+	if len(chars) == 0 {
+		return nil
+	}
+	return tables
+}
+
+func l33tMatches(pw []rune, dicts map[string]map[string]int) []*strengthMatch {
+	seen := make(map[string]bool)
+	var matches []*strengthMatch
+	for _, table := range l33tSubstitutions(pw) {
+		translated := lowerRunes(pw)
+		for i, r := range translated {
+			if letter, ok := table[r]; ok {
+				translated[i] = letter
+			}
+		}
+		for _, m := range dictionaryMatches(translated, dicts) {
+			token := pw[m.i : m.j+1]
+			used := make(map[rune]rune)
+			for _, r := range token {
+				if letter, ok := table[r]; ok {
+					used[r] = letter
+				}
+			}
+			// Single characters such as "1" = "i" are too noisy to count.
+			if len(used) == 0 || len(token) < 2 {
+				continue
+			}
+			key := m.dictionary + "\x00" + m.word + "\x00" + strconv.Itoa(m.i) + "\x00" + strconv.Itoa(m.j)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			m.token = string(token)
+			m.l33t = true
+			m.subs = used
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// keyboardGraph records, for each character, the keys next to it in each
+// direction ("" where there is none). Each key lists its unshifted and
+// shifted character.
+type keyboardGraph struct {
+	name      string
+	neighbors map[rune][]string
+	shifted   map[rune]bool
+	degree    float64
+}
+
+// Layouts are drawn as in zxcvbn: slanted rows are offset by one column
+// per row, and keys are separated by a space.
+const (
+	qwertyLayout = "`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+\n" +
+		"    qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|\n" +
+		"     aA sS dD fF gG hH jJ kK lL ;: '\"\n" +
+		"      zZ xX cC vV bB nN mM ,< .> /?"
+	keypadLayout = "  / * -\n" +
+		"7 8 9 +\n" +
+		"4 5 6\n" +
+		"1 2 3\n" +
+		"  0 ."
+)
+
+var keyboardGraphs = sync.OnceValue(func() []keyboardGraph {
+	return []keyboardGraph{
+		buildKeyboardGraph("qwerty", qwertyLayout, true),
+		buildKeyboardGraph("keypad", keypadLayout, false),
+	}
+})
+
+func buildKeyboardGraph(name, layout string, slanted bool) keyboardGraph {
+	type point struct{ x, y int }
+	keys := make(map[point]string)
+	for y, line := range strings.Split(layout, "\n") {
+		slant := 0
+		if slanted {
+			slant = y
+		}
+		for idx := 0; idx < len(line); idx++ {
+			if line[idx] == ' ' {
+				continue
+			}
+			end := strings.IndexByte(line[idx:], ' ')
+			if end < 0 {
+				end = len(line) - idx
+			}
+			key := line[idx : idx+end]
+			keys[point{(idx - slant) / (len(key) + 1), y}] = key
+			idx += end
+		}
+	}
+
+	directions := []point{{-1, 0}, {-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}}
+	if slanted {
+		directions = []point{{-1, 0}, {0, -1}, {1, -1}, {1, 0}, {0, 1}, {-1, 1}}
+	}
+	g := keyboardGraph{name: name, neighbors: make(map[rune][]string), shifted: make(map[rune]bool)}
+	total := 0
+	for p, key := range keys {
+		adjacent := make([]string, len(directions))
+		for d, dir := range directions {
+			adjacent[d] = keys[point{p.x + dir.x, p.y + dir.y}]
+			if adjacent[d] != "" {
+				total++
+			}
+		}
+		for k, r := range key {
+			g.neighbors[r] = adjacent
+			g.shifted[r] = k > 0
+		}
+	}
+	g.degree = float64(total) / float64(len(keys))
+	return g
+}
+
+// spatialMatches finds runs of at least three keys where each key is next
+// to the previous one, counting changes of direction and shifted keys.
+func spatialMatches(pw []rune, g keyboardGraph) []*strengthMatch {
+	var matches []*strengthMatch
+	for i := 0; i < len(pw)-1; {
+		j := i + 1
+		lastDirection, turns, shifted := -1, 0, 0
+		if g.shifted[pw[i]] {
+			shifted = 1
+		}
+		for {
+			found := false
+			if j < len(pw) {
+				for d, key := range g.neighbors[pw[j-1]] {
+					pos := strings.IndexRune(key, pw[j])
+					if key == "" || pos < 0 {
+						continue
+					}
+					found = true
+					if pos > 0 {
+						shifted++
+					}
+					if d != lastDirection {
+						turns++
+						lastDirection = d
+					}
+					break
+				}
+			}
+			if found {
+				j++
+				continue
+			}
+			if j-i > 2 {
+				matches = append(matches, &strengthMatch{
+					pattern: "spatial", i: i, j: j - 1, token: string(pw[i:j]),
+					graph: g.name, turns: turns, shifted: shifted,
+				})
+			}
+			i = j
+			break
+		}
+	}
+	return matches
+}
+
+// repeatMatches finds the longest run of a repeated unit at each position,
+// such as "aaa" or "abcabc". The unit itself is scored recursively.
+func repeatMatches(pw []rune, dicts map[string]map[string]int) []*strengthMatch {
+	var matches []*strengthMatch
+	for i := 0; i < len(pw); {
+		bestSpan, bestUnit := 0, 0
+		for unit := 1; i+2*unit <= len(pw); unit++ {
+			count := 1
+			for i+(count+1)*unit <= len(pw) && string(pw[i+count*unit:i+(count+1)*unit]) == string(pw[i:i+unit]) {
+				count++
+			}
+			if count >= 2 && count*unit > bestSpan {
+				bestSpan, bestUnit = count*unit, unit
+			}
+		}
+		if bestSpan == 0 {
+			i++
+			continue
+		}
+		base := pw[i : i+bestUnit]
+		matches = append(matches, &strengthMatch{
+			pattern: "repeat", i: i, j: i + bestSpan - 1, token: string(pw[i : i+bestSpan]),
+			base: string(base), baseGuesses: estimateStrength(base, dicts).guesses, repeats: bestSpan / bestUnit,
+		})
+		i += bestSpan
+	}
+	return matches
+}
+
+// maxSequenceDelta is the largest step between characters still treated as
+// a sequence, e.g. "aceg" or "9630".
+const maxSequenceDelta = 5
+
+// sequenceMatches finds runs where each character is a fixed step from the
+// previous one, such as "abcd", "9876", or "aceg".
+func sequenceMatches(pw []rune) []*strengthMatch {
+	if len(pw) < 2 {
+		return nil
+	}
+	var matches []*strengthMatch
+	add := func(i, j int, delta rune) {
+		abs := delta
+		if abs < 0 {
+			abs = -abs
+		}
+		if (j-i > 1 || abs == 1) && abs > 0 && abs <= maxSequenceDelta {
+			matches = append(matches, &strengthMatch{
+				pattern: "sequence", i: i, j: j, token: string(pw[i : j+1]), ascending: delta > 0,
+			})
+		}
+	}
+
+	i := 0
+	lastDelta := pw[1] - pw[0]
+	for k := 2; k < len(pw); k++ {
+		delta := pw[k] - pw[k-1]
+		if delta == lastDelta {
+			continue
+		}
+		add(i, k-1, lastDelta)
+		i, lastDelta = k-1, delta
+	}
+	add(i, len(pw)-1, lastDelta)
+	return matches
+}
+
+var recentYearPattern = regexp.MustCompile(`19\d\d|20[0-4]\d`)
+
+func yearMatches(pw []rune) []*strengthMatch {
+	s := string(pw)
+	var matches []*strengthMatch
+	for _, loc := range recentYearPattern.FindAllStringIndex(s, -1) {
+		i := utf8.RuneCountInString(s[:loc[0]])
+		year, _ := strconv.Atoi(s[loc[0]:loc[1]])
+		matches = append(matches, &strengthMatch{
+			pattern: "year", i: i, j: i + 3, token: s[loc[0]:loc[1]], year: year,
+		})
+	}
+	return matches
+}
+
+const (
+	minDateYear = 1000
+	maxDateYear = 2050
+)
+
+// dateSplits lists, for undelimited dates of each length, where the digits
+// can be split into three parts.
+var dateSplits = map[int][][2]int{
+	4: {{1, 2}, {2, 3}},
+	5: {{1, 3}, {2, 3}},
+	6: {{1, 2}, {2, 4}, {4, 5}},
+	7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+	8: {{2, 4}, {4, 6}},
+}
+
+var delimitedDatePattern = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+
+// dateMatches finds dates written with or without separators, such as
+// "13.05.1987", "1987-5-13", or "130587", in day/month/year order or any
+// other plausible order.
+func dateMatches(pw []rune) []*strengthMatch {
+	var matches []*strengthMatch
+	for i := range pw {
+		for j := i + 3; j < len(pw) && j < i+10; j++ {
+			token := string(pw[i : j+1])
+			if splits, ok := dateSplits[j-i+1]; ok && isDigits(token) {
+				var best *strengthMatch
+				for _, split := range splits {
+					date, ok := dateFromInts(atoiAll(token[:split[0]], token[split[0]:split[1]], token[split[1]:]))
+					if !ok {
+						continue
+					}
+					if best == nil || abs(date.year-referenceYear()) < abs(best.year-referenceYear()) {
+						best = date
+					}
+				}
+				if best != nil {
+					best.i, best.j, best.token = i, j, token
+					matches = append(matches, best)
+				}
+				continue
+			}
+			if j-i+1 < 6 {
+				continue
+			}
+			parts := delimitedDatePattern.FindStringSubmatch(token)
+			if parts == nil || parts[2] != parts[4] {
+				continue
+			}
+			if date, ok := dateFromInts(atoiAll(parts[1], parts[3], parts[5])); ok {
+				date.i, date.j, date.token, date.separator = i, j, token, parts[2]
+				matches = append(matches, date)
+			}
+		}
+	}
+
+	// Drop dates inside longer dates, e.g. "1/1/91" within "1/1/1991".
+	var kept []*strengthMatch
+	for _, m := range matches {
+		inside := false
+		for _, other := range matches {
+			if other != m && other.i <= m.i && other.j >= m.j && other.j-other.i > m.j-m.i {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// dateFromInts interprets three numbers as a day, month, and year in any
+// plausible order, with two-digit years mapped to 1951-2050.
+func dateFromInts(ints [3]int) (*strengthMatch, bool) {
+	if ints[1] > 31 || ints[1] <= 0 {
+		return nil, false
+	}
+	over12, over31, under1 := 0, 0, 0
+	for _, n := range ints {
+		if (n > 99 && n < minDateYear) || n > maxDateYear {
+			return nil, false
+		}
+		if n > 31 {
+			over31++
+		}
+		if n > 12 {
+			over12++
+		}
+		if n <= 0 {
+			under1++
+		}
+	}
+	if over31 >= 2 || over12 == 3 || under1 >= 2 {
+		return nil, false
+	}
+
+	yearSplits := []struct {
+		year int
+		rest [2]int
+	}{{ints[2], [2]int{ints[0], ints[1]}}, {ints[0], [2]int{ints[1], ints[2]}}}
+	for _, split := range yearSplits {
+		if split.year >= minDateYear && split.year <= maxDateYear {
+			day, month, ok := dayMonth(split.rest)
+			if !ok {
+				return nil, false
+			}
+			return &strengthMatch{pattern: "date", year: split.year, month: month, day: day}, true
+		}
+	}
+	for _, split := range yearSplits {
+		if day, month, ok := dayMonth(split.rest); ok {
+			year := split.year
+			switch {
+			case year > 99:
+			case year > 50:
+				year += 1900
+			default:
+				year += 2000
+			}
+			return &strengthMatch{pattern: "date", year: year, month: month, day: day}, true
+		}
+	}
+	return nil, false
+}
+
+func dayMonth(ints [2]int) (int, int, bool) {
+	for _, dm := range [][2]int{{ints[0], ints[1]}, {ints[1], ints[0]}} {
+		if dm[0] >= 1 && dm[0] <= 31 && dm[1] >= 1 && dm[1] <= 12 {
+			return dm[0], dm[1], true
+		}
+	}
+	return 0, 0, false
+}
+
+func atoiAll(a, b, c string) [3]int {
+	var out [3]int
+	for k, s := range []string{a, b, c} {
+		out[k], _ = strconv.Atoi(s)
+	}
+	return out
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
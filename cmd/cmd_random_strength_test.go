@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateStrength(t *testing.T) {
+	tests := []struct {
+		password string
+		patterns []string
+		score    int
+		warning  string
+	}{
+		{"password", []string{"dictionary"}, 0, "This is a top-10 common password"},
+		{"P@ssw0rd", []string{"dictionary"}, 0, "This is similar to a commonly used password"},
+		{"drowssap", []string{"dictionary"}, 0, "This is similar to a commonly used password"},
+		{"zxcvfr", []string{"spatial"}, 1, "Short keyboard patterns are easy to guess"},
+		{"78963214", []string{"spatial"}, 1, "Short keyboard patterns are easy to guess"},
+		{"aaaaaaaa", []string{"repeat"}, 0, `Repeats like "aaa" are easy to guess`},
+		{"ZYXWVU", []string{"sequence"}, 0, "Sequences like abc or 6543 are easy to guess"},
+		{"13.05.1987", []string{"date"}, 1, "Dates are often easy to guess"},
+		{"correcthorsebatterystaple", []string{"dictionary", "dictionary", "dictionary", "dictionary"}, 4, ""},
+		{"xK9#mq2$Lp", []string{"bruteforce"}, 3, ""},
+	}
+
+	dicts := loadStrengthDictionaries()
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			result := estimateStrength([]rune(tt.password), dicts)
+			var patterns []string
+			for _, m := range result.sequence {
+				patterns = append(patterns, m.pattern)
+			}
+			if strings.Join(patterns, " ") != strings.Join(tt.patterns, " ") {
+				t.Errorf("Expected patterns %v, got %v", tt.patterns, patterns)
+			}
+			if result.score != tt.score {
+				t.Errorf("Expected score %d, got %d (%g guesses)", tt.score, result.score, result.guesses)
+			}
+			if result.warning != tt.warning {
+				t.Errorf("Expected warning %q, got %q", tt.warning, result.warning)
+			}
+		})
+	}
+}
+
+func TestStrengthMatchers(t *testing.T) {
+	dicts := loadStrengthDictionaries()
+
+	l33t := l33tMatches([]rune("P@ssw0rd"), dicts)
+	found := false
+	for _, m := range l33t {
+		if m.word == "password" && m.i == 0 && m.j == 7 && m.subs['@'] == 'a' && m.subs['0'] == 'o' {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a l33t match for password")
+	}
+
+	walks := spatialMatches([]rune("1qaz2wsx"), keyboardGraphs()[0])
+	if len(walks) != 2 || walks[0].token != "1qaz" || walks[1].token != "2wsx" || walks[0].turns != 1 {
+		t.Errorf("Unexpected keyboard walks: %+v", walks)
+	}
+	shifted := spatialMatches([]rune("!QAZ"), keyboardGraphs()[0])
+	if len(shifted) != 1 || shifted[0].shifted != 4 {
+		t.Errorf("Expected 4 shifted keys, got %+v", shifted)
+	}
+
+	repeats := repeatMatches([]rune("xabcabcabcy"), dicts)
+	if len(repeats) != 1 || repeats[0].base != "abc" || repeats[0].repeats != 3 || repeats[0].i != 1 {
+		t.Errorf("Unexpected repeats: %+v", repeats)
+	}
+
+	for _, tt := range []struct {
+		token            string
+		year, month, day int
+	}{
+		{"1987-05-13", 1987, 5, 13},
+		{"13/5/87", 1987, 5, 13},
+		{"130587", 1987, 5, 13},
+		{"2001.12.31", 2001, 12, 31},
+	} {
+		dates := dateMatches([]rune(tt.token))
+		if len(dates) != 1 || dates[0].year != tt.year || dates[0].month != tt.month || dates[0].day != tt.day {
+			t.Errorf("%s: unexpected dates %+v", tt.token, dates)
+		}
+	}
+	if dates := dateMatches([]rune("99.99.99")); len(dates) != 0 {
+		t.Errorf("Expected no date, got %+v", dates)
+	}
+}
+
+func TestStrengthHelpers(t *testing.T) {
+	for _, tt := range []struct {
+		token string
+		want  float64
+	}{
+		{"password", 1},
+		{"Password", 2},
+		{"passworD", 2},
+		{"PASSWORD", 2},
+		{"PassWord", 28 + 8},
+	} {
+		if got := uppercaseVariations(tt.token); got != tt.want {
+			t.Errorf("uppercaseVariations(%q) = %g, want %g", tt.token, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		seconds float64
+		want    string
+	}{
+		{0.5, "less than a second"},
+		{1, "1 second"},
+		{90, "2 minutes"},
+		{3 * 86400, "3 days"},
+		{1e12, "centuries"},
+	} {
+		if got := displayCrackTime(tt.seconds); got != tt.want {
+			t.Errorf("displayCrackTime(%g) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+
+	dicts := withUserInputs(loadStrengthDictionaries(), []string{"jane.doe@acme.com"})
+	result := estimateStrength([]rune("acme"), dicts)
+	if len(result.sequence) != 1 || result.sequence[0].dictionary != "user_inputs" {
+		t.Errorf("Expected a user input match, got %+v", result.sequence)
+	}
+}
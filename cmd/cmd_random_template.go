@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// newTemplateGenerator parses a --template (or @file) and returns a
+// generator whose values are records rendered from it. Each record must be
+// valid JSON; it is checked with the same parser as "plz json" and emitted
+// compacted with its key order intact.
+func newTemplateGenerator(text string) (randomGenerator, error) {
+	if strings.HasPrefix(text, "@") {
+		data, err := os.ReadFile(text[1:])
+		if err != nil {
+			return randomGenerator{}, fmt.Errorf("failed to read template %s: %w", text[1:], err)
+		}
+		text = string(data)
+	}
+
+	wordlist, err := loadWordlist("")
+	if err != nil {
+		return randomGenerator{}, err
+	}
+
+	seq := 0
+	tmpl, err := template.New("record").Funcs(templateFuncs(&seq, wordlist)).Parse(text)
+	if err != nil {
+		return randomGenerator{}, fmt.Errorf("invalid template: %w", err)
+	}
+
+	return randomGenerator{
+		name:   "record",
+		label:  "Random record",
+		column: "record",
+		record: true,
+		next: func() (string, error) {
+			seq++
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, nil); err != nil {
+				return "", err
+			}
+			if _, err := parseJSON(buf.Bytes(), true); err != nil {
+				return "", fmt.Errorf("template produced %w: %s", err, buf.String())
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, buf.Bytes()); err != nil {
+				return "", err
+			}
+			return compact.String(), nil
+		},
+	}, nil
+}
+
+// templateFuncs are the generators available inside a template. String
+// results are JSON-escaped so they can be placed inside quotes; numeric
+// results are bare so they can be used as JSON numbers. wordlist backs
+// passphrase.
+func templateFuncs(seq *int, wordlist []string) template.FuncMap {
+	fakers := make(map[string]*faker)
+	patterns := make(map[string]*regexGenerator)
+	fake := func(kind string, locale ...string) (string, error) {
+		name := defaultFakeLocale()
+		if len(locale) > 0 {
+			name = locale[0]
+		}
+		f, ok := fakers[name]
+		if !ok {
+			var err error
+			if f, err = newFaker(name); err != nil {
+				return "", err
+			}
+			fakers[name] = f
+		}
+		value, err := f.generate(kind)
+		return jsonEscape(value), err
+	}
+
+	funcs := template.FuncMap{
+		"seq": func() int { return *seq },
+		"uuid": func(version ...int) (string, error) {
+			v := 4
+			if len(version) > 0 {
+				v = version[0]
+			}
+			return generateUUIDVersion(v, "", "")
+		},
+		"int": func(minVal, maxVal interface{}) (string, error) {
+			lo, hi, err := templateRange(minVal, maxVal)
+			if err != nil {
+				return "", err
+			}
+			if !lo.IsInt() || !hi.IsInt() {
+				return "", fmt.Errorf("int bounds must be integers")
+			}
+			if hi.Cmp(lo) < 0 {
+				return "", fmt.Errorf("int max must not be less than min")
+			}
+			n, err := generateRandomBigInt(lo.Num(), new(big.Int).Add(hi.Num(), big.NewInt(1)))
+			if err != nil {
+				return "", err
+			}
+			return n.String(), nil
+		},
+		"float": func(minVal, maxVal interface{}, precision ...int) (string, error) {
+			lo, hi, err := templateRange(minVal, maxVal)
+			if err != nil {
+				return "", err
+			}
+			if hi.Cmp(lo) <= 0 {
+				return "", fmt.Errorf("float max must be greater than min")
+			}
+			p := 2
+			if len(precision) > 0 {
+				p = precision[0]
+			}
+			gen, err := newUniformFloatGenerator(lo, hi, p)
+			if err != nil {
+				return "", err
+			}
+			return gen.next()
+		},
+		"bool": func() (string, error) {
+			n, err := randomIndex(2)
+			return fmt.Sprint(n == 1), err
+		},
+		"choice": func(options ...interface{}) (string, error) {
+			if len(options) == 0 {
+				return "", fmt.Errorf("choice needs at least one option")
+			}
+			n, err := randomIndex(len(options))
+			if err != nil {
+				return "", err
+			}
+			return jsonEscape(fmt.Sprint(options[n])), nil
+		},
+		"string": func(length ...int) (string, error) {
+			n := 16
+			if len(length) > 0 {
+				n = length[0]
+			}
+			return generateRandomString(n)
+		},
+		"passphrase": func(words ...int) (string, error) {
+			opts := passphraseOptions{words: 6, separator: "-"}
+			if len(words) > 0 {
+				opts.words = words[0]
+			}
+			return generatePassphrase(wordlist, opts)
+		},
+		"bytes": func(size ...int) (string, error) {
+			b := make([]byte, 16)
+			if len(size) > 0 {
+				b = make([]byte, size[0])
+			}
+			if err := randomBytes(b); err != nil {
+				return "", err
+			}
+			return encodeBytes(b, "hex")
+		},
+		"regex": func(pattern string) (string, error) {
+			gen, ok := patterns[pattern]
+			if !ok {
+				var err error
+				if gen, err = newRegexGenerator(pattern, defaultMaxRepeat); err != nil {
+					return "", err
+				}
+				patterns[pattern] = gen
+			}
+			value, err := gen.generate()
+			return jsonEscape(value), err
+		},
+		"ulid":     func() (string, error) { return generateULID(time.Now()) },
+		"ksuid":    func() (string, error) { return generateKSUID(time.Now()) },
+		"nanoid":   func() (string, error) { return generateNanoID([]rune(nanoidAlphabet), nanoidSize) },
+		"objectid": func() (string, error) { return generateObjectID(time.Now()) },
+		"fake":     fake,
+	}
+
+	// Every fake kind is also a function of its own, camel-cased because
+	// template identifiers cannot contain hyphens (first-name -> firstName).
+	for _, kind := range fakeKindNames {
+		kind := kind
+		funcs[camelCase(kind)] = func(locale ...string) (string, error) { return fake(kind, locale...) }
+	}
+	return funcs
+}
+
+// templateRange parses numeric template arguments, which may be numbers
+// or strings such as "9e18".
+func templateRange(minVal, maxVal interface{}) (*big.Rat, *big.Rat, error) {
+	lo, err := parseDecimal(fmt.Sprint(minVal))
+	if err != nil {
+		return nil, nil, err
+	}
+	hi, err := parseDecimal(fmt.Sprint(maxVal))
+	if err != nil {
+		return nil, nil, err
+	}
+	return lo, hi, nil
+}
+
+// jsonEscape returns s escaped for use inside a JSON string literal.
+func jsonEscape(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	out := strings.TrimSuffix(buf.String(), "\n")
+	return out[1 : len(out)-1]
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "-")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = titleWord(parts[i])
+	}
+	return strings.Join(parts, "")
+}
@@ -2,12 +2,23 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
 	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 )
@@ -16,6 +27,7 @@ func TestRandomCommand(t *testing.T) {
 	tests := []struct {
 		name     string
 		flags    map[string]string
+		args     []string
 		validate func(string) bool
 		wantErr  bool
 	}{
@@ -24,7 +36,12 @@ func TestRandomCommand(t *testing.T) {
 			flags: map[string]string{"type": "string"},
 			validate: func(output string) bool {
 				// Should have format: "Random string (16 chars): <string>"
-				parts := strings.Split(output, ": ")
+				// followed by an entropy line
+				lines := strings.Split(output, "\n")
+				if len(lines) != 2 || !strings.HasPrefix(lines[1], "Entropy: 95.3 bits") {
+					return false
+				}
+				parts := strings.Split(lines[0], ": ")
 				if len(parts) != 2 {
 					return false
 				}
@@ -39,7 +56,7 @@ func TestRandomCommand(t *testing.T) {
 			name:  "random string custom length",
 			flags: map[string]string{"type": "string", "length": "10"},
 			validate: func(output string) bool {
-				parts := strings.Split(output, ": ")
+				parts := strings.Split(strings.Split(output, "\n")[0], ": ")
 				if len(parts) != 2 {
 					return false
 				}
@@ -101,6 +118,238 @@ func TestRandomCommand(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "random string hex alphabet",
+			flags: map[string]string{"type": "string", "alphabet": "hex", "length": "8"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 2 &&
+					regexp.MustCompile(`^Random string \(8 chars\): [0-9a-f]{8}$`).MatchString(lines[0]) &&
+					lines[1] == "Entropy: 32.0 bits (16-character alphabet)"
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random string custom charset without ambiguous characters",
+			flags: map[string]string{"type": "string", "charset": "0O1lab", "exclude-ambiguous": "true", "length": "6"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 2 &&
+					regexp.MustCompile(`^Random string \(6 chars\): [ab]{6}$`).MatchString(lines[0]) &&
+					lines[1] == "Entropy: 6.0 bits (2-character alphabet)"
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random string with policy",
+			flags: map[string]string{"type": "string", "alphabet": "password", "require": "digit,symbol", "length": "4"},
+			validate: func(output string) bool {
+				value := strings.TrimPrefix(strings.Split(output, "\n")[0], "Random string (4 chars): ")
+				return len(value) == 4 && strings.ContainsAny(value, digitChars) && strings.ContainsAny(value, symbolChars)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random passphrase default",
+			flags: map[string]string{"type": "passphrase"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 2 &&
+					regexp.MustCompile(`^Random passphrase \(6 words\): [a-z]+(-[a-z]+){5}$`).MatchString(lines[0]) &&
+					lines[1] == "Entropy: 77.5 bits (7776-word list)"
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random passphrase custom wordlist",
+			flags: map[string]string{"type": "passphrase", "wordlist": "testdata/wordlist.txt", "words": "3", "separator": " ", "capitalize": "upper"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 2 &&
+					regexp.MustCompile(`^Random passphrase \(3 words\): (ALPHA|BRAVO|CHARLIE|DELTA)( (ALPHA|BRAVO|CHARLIE|DELTA)){2}$`).MatchString(lines[0]) &&
+					lines[1] == "Entropy: 6.0 bits (4-word list)"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "random passphrase invalid capitalization",
+			flags:    map[string]string{"type": "passphrase", "capitalize": "sideways"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "unsatisfiable policy",
+			flags:    map[string]string{"type": "string", "alphabet": "lower", "require": "digit"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "unknown alphabet",
+			flags:    map[string]string{"type": "string", "alphabet": "klingon"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "uuid v5",
+			flags: map[string]string{"type": "uuid", "uuid-version": "5", "namespace": "url", "name": "https://example.com"},
+			validate: func(output string) bool {
+				return output == "UUID v5: 4fd35a71-71ef-5a55-a9d9-aa75c889a6d0"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "invalid uuid version",
+			flags:    map[string]string{"type": "uuid", "uuid-version": "9"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "random ulid",
+			flags: map[string]string{"type": "ulid"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Random ULID: [0-7][0-9A-HJKMNP-TV-Z]{25}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random ksuid",
+			flags: map[string]string{"type": "ksuid"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Random KSUID: [0-9A-Za-z]{27}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random nanoid default size",
+			flags: map[string]string{"type": "nanoid"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Random NanoID: [A-Za-z0-9_-]{21}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random nanoid custom alphabet and size",
+			flags: map[string]string{"type": "nanoid", "charset": "xyz", "length": "8"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Random NanoID: [xyz]{8}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "random snowflake with worker",
+			flags: map[string]string{"type": "snowflake", "worker": "42"},
+			validate: func(output string) bool {
+				id, err := strconv.ParseInt(strings.TrimPrefix(output, "Random Snowflake: "), 10, 64)
+				return err == nil && id>>12&1023 == 42
+			},
+			wantErr: false,
+		},
+		{
+			name:     "random snowflake invalid worker",
+			flags:    map[string]string{"type": "snowflake", "worker": "2048"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "random objectid",
+			flags: map[string]string{"type": "objectid"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Random ObjectID: [0-9a-f]{24}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "bulk strings as lines",
+			flags: map[string]string{"type": "string", "length": "8", "count": "5"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				if len(lines) != 5 {
+					return false
+				}
+				for _, line := range lines {
+					if !regexp.MustCompile(`^[A-Za-z0-9]{8}$`).MatchString(line) {
+						return false
+					}
+				}
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "bulk unique numbers as json",
+			flags: map[string]string{"type": "number", "min": "0", "max": "10", "count": "10", "unique": "true", "output": "json"},
+			validate: func(output string) bool {
+				var numbers []int
+				if err := json.Unmarshal([]byte(output), &numbers); err != nil || len(numbers) != 10 {
+					return false
+				}
+				sort.Ints(numbers)
+				for i, n := range numbers {
+					if n != i {
+						return false
+					}
+				}
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "bulk uuids as csv",
+			flags: map[string]string{"type": "uuid", "count": "2", "output": "csv"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 3 && lines[0] == "uuid" && len(lines[1]) == 36 && len(lines[2]) == 36
+			},
+			wantErr: false,
+		},
+		{
+			name:  "single value as lines has no label",
+			flags: map[string]string{"type": "number", "min": "7", "max": "8", "output": "lines"},
+			validate: func(output string) bool {
+				return output == "7"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "unique space too small",
+			flags:    map[string]string{"type": "number", "min": "0", "max": "3", "count": "4", "unique": "true"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "unique nanoids over a deduplicated charset",
+			flags:    map[string]string{"type": "nanoid", "charset": "aab", "length": "2", "count": "5", "unique": "true"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "unique name-based uuids",
+			flags:    map[string]string{"type": "uuid", "uuid-version": "5", "namespace": "dns", "name": "example.com", "count": "2", "unique": "true"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "invalid count",
+			flags:    map[string]string{"count": "0"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "invalid output format",
+			flags:    map[string]string{"output": "xml"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "seeded string is reproducible and marked",
+			flags: map[string]string{"type": "string", "seed": "abc"},
+			validate: func(output string) bool {
+				return output == "Random string (16 chars): 4AgQ7D0sPED8C5qa\n"+
+					"Entropy: 95.3 bits (62-character alphabet)\n"+
+					seedWarning
+			},
+			wantErr: false,
+		},
 		{
 			name:     "invalid random type",
 			flags:    map[string]string{"type": "invalid"},
@@ -108,76 +357,525 @@ func TestRandomCommand(t *testing.T) {
 			wantErr:  true,
 		},
 		{
-			name:     "invalid number range",
-			flags:    map[string]string{"type": "number", "min": "100", "max": "50"},
+			name:  "number range beyond int64",
+			flags: map[string]string{"type": "number", "min": "-9e18", "max": "9e18"},
+			validate: func(output string) bool {
+				prefix := "Random number (-9000000000000000000-9000000000000000000): "
+				if !strings.HasPrefix(output, prefix) {
+					return false
+				}
+				n, ok := new(big.Int).SetString(strings.TrimPrefix(output, prefix), 10)
+				return ok && n.CmpAbs(big.NewInt(9e18)) <= 0
+			},
+			wantErr: false,
+		},
+		{
+			name:  "float with precision",
+			flags: map[string]string{"type": "float", "min": "-1", "max": "1", "precision": "3", "count": "20", "output": "lines"},
+			validate: func(output string) bool {
+				for _, line := range strings.Split(output, "\n") {
+					f, err := strconv.ParseFloat(line, 64)
+					if err != nil || f < -1 || f >= 1 || len(line[strings.Index(line, ".")+1:]) != 3 {
+						return false
+					}
+				}
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "bounded normal distribution",
+			flags: map[string]string{"type": "number", "distribution": "normal", "mean": "50", "stddev": "20", "min": "40", "max": "60", "count": "50", "output": "lines"},
+			validate: func(output string) bool {
+				for _, line := range strings.Split(output, "\n") {
+					n, err := strconv.Atoi(line)
+					if err != nil || n < 40 || n >= 60 {
+						return false
+					}
+				}
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "weighted choice as JSON numbers",
+			flags: map[string]string{"type": "weighted", "weights": "1:1,2:0", "count": "3", "output": "json"},
+			validate: func(output string) bool {
+				return strings.Join(strings.Fields(output), "") == "[1,1,1]"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "decimal bounds for integers",
+			flags:    map[string]string{"type": "number", "min": "1.5"},
 			validate: func(output string) bool { return true },
 			wantErr:  true,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Reset flags to default values
-			randomType = "string"
-			randomLength = 16
-			randomMin = 0
-			randomMax = 100
-
-			// Create a new command instance for testing
-			cmd := &cobra.Command{
-				Use:  "random",
-				RunE: runRandom,
-			}
-
-			// Add flags
-			cmd.Flags().StringVarP(&randomType, "type", "t", "string", "Type: string, number, uuid")
-			cmd.Flags().IntVarP(&randomLength, "length", "l", 16, "Length for string generation")
-			cmd.Flags().Int64Var(&randomMin, "min", 0, "Minimum value for number generation")
-			cmd.Flags().Int64Var(&randomMax, "max", 100, "Maximum value for number generation")
-
-			// Set flags
-			for flag, value := range tt.flags {
-				cmd.Flags().Set(flag, value)
-			}
-
-			// Capture output
-			old := os.Stdout
-			r, w, _ := os.Pipe()
-			os.Stdout = w
-
-			// Run command
-			err := cmd.RunE(cmd, []string{})
-
-			// Restore stdout and get output
-			w.Close()
-			os.Stdout = old
-			var buf bytes.Buffer
-			io.Copy(&buf, r)
-			output := strings.TrimSpace(buf.String())
-
-			// Check results
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Expected error, but got none")
+		{
+			name:     "poisson floats",
+			flags:    map[string]string{"type": "float", "distribution": "poisson", "mean": "3"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "weighted without weights",
+			flags:    map[string]string{"type": "weighted"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "bytes default to 32 hex-encoded",
+			flags: map[string]string{"type": "bytes"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^Random bytes \(32, hex\): [0-9a-f]{64}\nEntropy: 256 bits$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "bytes as base64url",
+			flags: map[string]string{"type": "bytes", "length": "16", "encoding": "base64url", "output": "lines"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^[A-Za-z0-9_-]{22}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "raw bytes",
+			flags:    map[string]string{"type": "bytes", "length": "5", "encoding": "raw"},
+			validate: func(output string) bool { return true },
+			wantErr:  false,
+		},
+		{
+			name:     "raw bytes as JSON",
+			flags:    map[string]string{"type": "bytes", "encoding": "raw", "output": "json"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported bytes encoding",
+			flags:    map[string]string{"type": "bytes", "encoding": "base58"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "template records as NDJSON",
+			flags: map[string]string{"template": `{"n":{{seq}},"id":"{{uuid}}","age":{{int 18 90}},"email":"{{email}}"}`, "count": "3"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				if len(lines) != 3 {
+					return false
 				}
-			} else {
-				if err != nil {
-					t.Errorf("Unexpected error: %v", err)
+				for i, line := range lines {
+					var record struct {
+						N     int    `json:"n"`
+						ID    string `json:"id"`
+						Age   int    `json:"age"`
+						Email string `json:"email"`
+					}
+					if err := json.Unmarshal([]byte(line), &record); err != nil {
+						return false
+					}
+					if record.N != i+1 || len(record.ID) != 36 || record.Age < 18 || record.Age > 90 || !strings.Contains(record.Email, "@example.") {
+						return false
+					}
+					if !strings.HasPrefix(line, `{"n":`) {
+						return false
+					}
 				}
-				if !tt.validate(output) {
-					t.Errorf("Output validation failed for: %q", output)
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "template records as a JSON array with escaping",
+			flags: map[string]string{"template": `{"q":"{{choice "say \"hi\""}}","b":{{bool}}}`, "count": "2", "output": "json"},
+			validate: func(output string) bool {
+				var records []map[string]interface{}
+				if err := json.Unmarshal([]byte(output), &records); err != nil || len(records) != 2 {
+					return false
 				}
-			}
-		})
-	}
-}
-
-func TestRandomFunctions(t *testing.T) {
-	t.Run("generateRandomString", func(t *testing.T) {
-		result, err := generateRandomString(10)
-		if err != nil {
-			t.Errorf("Unexpected error: %v", err)
-		}
+				return records[0]["q"] == `say "hi"`
+			},
+			wantErr: false,
+		},
+		{
+			name:     "template producing invalid JSON",
+			flags:    map[string]string{"template": `{"email":{{email}}}`},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "template with unknown generator",
+			flags:    map[string]string{"template": `{"x":{{ssn}}}`},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "regex matches",
+			flags: map[string]string{"type": "regex", "pattern": `[A-Z]{3}-\d{4}`, "count": "20", "unique": "true"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				for _, line := range lines {
+					if !regexp.MustCompile(`^[A-Z]{3}-\d{4}$`).MatchString(line) {
+						return false
+					}
+				}
+				return len(lines) == 20
+			},
+			wantErr: false,
+		},
+		{
+			name:  "regex repeat limit",
+			flags: map[string]string{"type": "regex", "pattern": `^x+$`, "max-repeat": "2", "count": "20"},
+			validate: func(output string) bool {
+				return regexp.MustCompile(`^(x{1,3}\n){19}x{1,3}$`).MatchString(output)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "single regex match is labelled",
+			flags: map[string]string{"type": "regex", "pattern": `a|b`},
+			validate: func(output string) bool {
+				return output == "Random match for a|b: a" || output == "Random match for a|b: b"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "regex without pattern",
+			flags:    map[string]string{"type": "regex"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "invalid regex",
+			flags:    map[string]string{"type": "regex", "pattern": `(a`},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "single dice roll shows the dice",
+			flags: map[string]string{"type": "dice"},
+			args:  []string{"4d6kh3+2"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 2 && regexp.MustCompile(`^Dice roll \(4d6kh3\+2\): (\d+)$`).MatchString(lines[0]) &&
+					regexp.MustCompile(`^Rolls: 4d6kh3 \[[1-6 ()]+\] \+ 2$`).MatchString(lines[1])
+			},
+			wantErr: false,
+		},
+		{
+			name:  "dice totals as JSON numbers",
+			flags: map[string]string{"type": "dice", "count": "20", "output": "json"},
+			args:  []string{"2d6"},
+			validate: func(output string) bool {
+				var totals []int
+				if err := json.Unmarshal([]byte(output), &totals); err != nil || len(totals) != 20 {
+					return false
+				}
+				for _, total := range totals {
+					if total < 2 || total > 12 {
+						return false
+					}
+				}
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "dice odds",
+			flags: map[string]string{"type": "dice", "odds": "true"},
+			args:  []string{"2d6"},
+			validate: func(output string) bool {
+				return strings.HasPrefix(output, "Distribution of 2d6 (mean 7.00, std dev 2.42)") &&
+					strings.Contains(output, "    7   16.6667%   58.3333%")
+			},
+			wantErr: false,
+		},
+		{
+			name:     "dice without expression",
+			flags:    map[string]string{"type": "dice"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "odds for another type",
+			flags:    map[string]string{"type": "number", "odds": "true"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "unique addresses fill a small network",
+			flags: map[string]string{"type": "ip", "cidr": "192.0.2.0/29", "count": "6", "unique": "true", "output": "lines"},
+			validate: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				sort.Strings(lines)
+				return strings.Join(lines, " ") == "192.0.2.1 192.0.2.2 192.0.2.3 192.0.2.4 192.0.2.5 192.0.2.6"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "more unique addresses than hosts",
+			flags:    map[string]string{"type": "ip", "cidr": "192.0.2.0/29", "count": "7", "unique": "true"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "overlapping networks are counted once",
+			flags:    map[string]string{"type": "ip", "cidr": "192.0.2.0/29,192.0.2.4/30,192.0.2.0/29", "count": "7", "unique": "true"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "ip without cidr",
+			flags:    map[string]string{"type": "ip"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "locally administered MAC",
+			flags: map[string]string{"type": "mac", "count": "50", "output": "lines"},
+			validate: func(output string) bool {
+				for _, line := range strings.Split(output, "\n") {
+					mac, err := net.ParseMAC(line)
+					if err != nil || len(mac) != 6 || mac[0]&0x03 != 0x02 {
+						return false
+					}
+				}
+				return true
+			},
+			wantErr: false,
+		},
+		{
+			name:  "ports within ranges as JSON numbers",
+			flags: map[string]string{"type": "port", "ports": "80,443,8000-8002", "count": "5", "unique": "true", "output": "json"},
+			validate: func(output string) bool {
+				var ports []int
+				if err := json.Unmarshal([]byte(output), &ports); err != nil || len(ports) != 5 {
+					return false
+				}
+				sort.Ints(ports)
+				return fmt.Sprint(ports) == "[80 443 8000 8001 8002]"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "invalid port range",
+			flags:    map[string]string{"type": "port", "ports": "9000-8000"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:  "classic lorem paragraphs",
+			flags: map[string]string{"type": "lorem", "paragraphs": "2"},
+			validate: func(output string) bool {
+				paragraphs := strings.Split(output, "\n\n")
+				return len(paragraphs) == 2 &&
+					strings.HasPrefix(paragraphs[0], "Lorem ipsum dolor sit amet, consectetur adipiscing elit. ") &&
+					!strings.Contains(paragraphs[1], "Lorem ipsum") &&
+					strings.HasSuffix(paragraphs[1], ".")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "lorem words",
+			flags: map[string]string{"type": "lorem", "lorem-words": "3"},
+			validate: func(output string) bool {
+				return output == "Lorem ipsum dolor"
+			},
+			wantErr: false,
+		},
+		{
+			name:  "lorem sentences as HTML",
+			flags: map[string]string{"type": "lorem", "sentences": "3", "markup": "html", "lorem-mode": "random"},
+			validate: func(output string) bool {
+				return strings.HasPrefix(output, "<p>") && strings.HasSuffix(output, ".</p>") &&
+					strings.Count(output, ". ") == 2 && !strings.Contains(output, "Lorem")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "lorem random words from a wordlist",
+			flags: map[string]string{"type": "lorem", "lorem-words": "4", "lorem-mode": "random", "wordlist": "testdata/wordlist.txt"},
+			validate: func(output string) bool {
+				return len(strings.Fields(output)) == 4
+			},
+			wantErr: false,
+		},
+		{
+			name:  "lorem as Markdown",
+			flags: map[string]string{"type": "lorem", "paragraphs": "3", "markup": "markdown"},
+			validate: func(output string) bool {
+				for _, line := range strings.Split(output, "\n") {
+					if len(line) > 80 {
+						return false
+					}
+				}
+				return strings.Count(output, "\n\n") == 2
+			},
+			wantErr: false,
+		},
+		{
+			name:  "lorem count as JSON",
+			flags: map[string]string{"type": "lorem", "lorem-words": "2", "count": "3", "output": "json"},
+			validate: func(output string) bool {
+				var values []string
+				return json.Unmarshal([]byte(output), &values) == nil && len(values) == 3 && values[0] == "Lorem ipsum"
+			},
+			wantErr: false,
+		},
+		{
+			name:     "lorem with two units",
+			flags:    map[string]string{"type": "lorem", "lorem-words": "3", "sentences": "2"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "lorem with unsupported markup",
+			flags:    map[string]string{"type": "lorem", "markup": "rtf"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+		{
+			name:     "invalid number range",
+			flags:    map[string]string{"type": "number", "min": "100", "max": "50"},
+			validate: func(output string) bool { return true },
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags to default values
+			randomType = "string"
+			randomLength = 16
+			randomMin = "0"
+			randomMax = "100"
+			randomDistribution = "uniform"
+			randomMean = 0
+			randomStddev = 1
+			randomPrecision = 2
+			randomWeights = nil
+			randomEncoding = "hex"
+			randomTemplate = ""
+			randomPattern = ""
+			randomMaxRepeat = defaultMaxRepeat
+			randomAlphabet = "alphanumeric"
+			randomCharset = ""
+			randomExcludeAmbiguous = false
+			randomRequire = nil
+			randomWords = 6
+			randomSeparator = "-"
+			randomCapitalize = "none"
+			randomDigits = 0
+			randomWordlist = ""
+			randomUUIDVersion = 4
+			randomNamespace = ""
+			randomName = ""
+			randomEpoch = twitterEpoch
+			randomWorker = 0
+			randomCount = 1
+			randomOutput = "text"
+			randomUnique = false
+			randomSeed = ""
+			randomOdds = false
+			randomCIDRs = nil
+			randomPorts = []string{"1024-65535"}
+			randomLoremWords = 0
+			randomSentences = 0
+			randomParagraphs = 3
+			randomLoremMode = "classic"
+			randomMarkup = "text"
+
+			// Create a new command instance for testing
+			cmd := &cobra.Command{
+				Use:  "random",
+				RunE: runRandom,
+			}
+
+			// Add flags
+			cmd.Flags().StringVarP(&randomType, "type", "t", "string", "Type: string, number, uuid")
+			cmd.Flags().IntVarP(&randomLength, "length", "l", 16, "Length for string generation")
+			cmd.Flags().StringVar(&randomMin, "min", "0", "Minimum value for numbers and floats")
+			cmd.Flags().StringVar(&randomMax, "max", "100", "Maximum value for numbers and floats")
+			cmd.Flags().StringVar(&randomDistribution, "distribution", "uniform", "Distribution for numbers and floats")
+			cmd.Flags().Float64Var(&randomMean, "mean", 0, "Mean for non-uniform distributions")
+			cmd.Flags().Float64Var(&randomStddev, "stddev", 1, "Standard deviation for the normal distribution")
+			cmd.Flags().IntVar(&randomPrecision, "precision", 2, "Decimal places for floats")
+			cmd.Flags().StringSliceVar(&randomWeights, "weights", nil, "Choices for --type weighted")
+			cmd.Flags().StringVarP(&randomEncoding, "encoding", "e", "hex", "Encoding for bytes")
+			cmd.Flags().BoolVar(&randomOdds, "odds", false, "Print the exact distribution of a dice expression")
+			cmd.Flags().StringSliceVar(&randomCIDRs, "cidr", nil, "Networks for --type ip")
+			cmd.Flags().StringSliceVar(&randomPorts, "ports", []string{"1024-65535"}, "Ports and ranges for --type port")
+			cmd.Flags().IntVar(&randomLoremWords, "lorem-words", 0, "Number of words for --type lorem")
+			cmd.Flags().IntVar(&randomSentences, "sentences", 0, "Number of sentences for --type lorem")
+			cmd.Flags().IntVar(&randomParagraphs, "paragraphs", 3, "Number of paragraphs for --type lorem")
+			cmd.Flags().StringVar(&randomLoremMode, "lorem-mode", "classic", "Words for --type lorem")
+			cmd.Flags().StringVar(&randomMarkup, "markup", "text", "Markup for --type lorem")
+			cmd.Flags().StringVar(&randomTemplate, "template", "", "JSON record template")
+			cmd.Flags().StringVar(&randomPattern, "pattern", "", "Regular expression for --type regex")
+			cmd.Flags().IntVar(&randomMaxRepeat, "max-repeat", defaultMaxRepeat, "Extra repetitions allowed")
+			cmd.Flags().StringVarP(&randomAlphabet, "alphabet", "a", "alphanumeric", "Alphabet for strings")
+			cmd.Flags().StringVar(&randomCharset, "charset", "", "Custom characters for strings (overrides --alphabet)")
+			cmd.Flags().BoolVar(&randomExcludeAmbiguous, "exclude-ambiguous", false, "Exclude look-alike characters")
+			cmd.Flags().StringSliceVar(&randomRequire, "require", nil, "Require at least one character of each class")
+			cmd.Flags().IntVarP(&randomWords, "words", "w", 6, "Number of words for passphrase generation")
+			cmd.Flags().StringVar(&randomSeparator, "separator", "-", "Separator between passphrase words")
+			cmd.Flags().StringVar(&randomCapitalize, "capitalize", "none", "Passphrase capitalization")
+			cmd.Flags().IntVar(&randomDigits, "digits", 0, "Number of random digits to insert into the passphrase")
+			cmd.Flags().StringVar(&randomWordlist, "wordlist", "", "Wordlist file for passphrases")
+			cmd.Flags().IntVar(&randomUUIDVersion, "uuid-version", 4, "UUID version: 1, 3, 4, 5, 6, 7")
+			cmd.Flags().StringVar(&randomNamespace, "namespace", "", "Namespace for UUID v3/v5")
+			cmd.Flags().StringVar(&randomName, "name", "", "Name for UUID v3/v5")
+			cmd.Flags().Int64Var(&randomEpoch, "epoch", twitterEpoch, "Snowflake epoch in Unix milliseconds")
+			cmd.Flags().Int64Var(&randomWorker, "worker", 0, "Snowflake worker ID (0-1023)")
+			cmd.Flags().IntVarP(&randomCount, "count", "n", 1, "Number of values to generate")
+			cmd.Flags().StringVarP(&randomOutput, "output", "o", "text", "Output format: text, lines, json, csv")
+			cmd.Flags().BoolVarP(&randomUnique, "unique", "u", false, "Guarantee no duplicates within the batch")
+			cmd.Flags().StringVar(&randomSeed, "seed", "", "Seed for deterministic, non-cryptographic output")
+
+			// Set flags
+			for flag, value := range tt.flags {
+				cmd.Flags().Set(flag, value)
+			}
+
+			// Capture output
+			old := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			// Run command
+			err := cmd.RunE(cmd, tt.args)
+
+			// Restore stdout and get output
+			w.Close()
+			os.Stdout = old
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := strings.TrimSpace(buf.String())
+
+			// Check results
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if !tt.validate(output) {
+					t.Errorf("Output validation failed for: %q", output)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomFunctions(t *testing.T) {
+	t.Run("generateRandomString", func(t *testing.T) {
+		result, err := generateRandomString(10)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
 		if len(result) != 10 {
 			t.Errorf("Expected length 10, got %d", len(result))
 		}
@@ -212,3 +910,631 @@ func TestRandomFunctions(t *testing.T) {
 		}
 	})
 }
+
+func TestStringPolicy(t *testing.T) {
+	t.Run("combinations with requirements", func(t *testing.T) {
+		policy, err := newStringPolicy("", "ab01", false, []string{"digit"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// 4^3 strings minus the 2^3 with no digit
+		if got := policy.combinations(3).Int64(); got != 56 {
+			t.Errorf("Expected 56 combinations, got %d", got)
+		}
+	})
+
+	t.Run("too short for policy", func(t *testing.T) {
+		policy, err := newStringPolicy("password", "", false, []string{"lower", "upper", "digit"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := policy.generate(2); err == nil {
+			t.Error("Expected error when length is shorter than the number of required classes")
+		}
+	})
+
+	t.Run("unknown class", func(t *testing.T) {
+		if _, err := newStringPolicy("alphanumeric", "", false, []string{"emoji"}); err == nil {
+			t.Error("Expected error for unknown character class")
+		}
+	})
+}
+
+func TestPassphrase(t *testing.T) {
+	t.Run("embedded wordlist", func(t *testing.T) {
+		words, err := loadWordlist("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(words) != 7776 || words[0] != "abacus" || words[len(words)-1] != "zoom" {
+			t.Errorf("Unexpected embedded wordlist: %d words", len(words))
+		}
+	})
+
+	t.Run("digits are inserted", func(t *testing.T) {
+		opts := passphraseOptions{words: 2, separator: "_", digits: 3}
+		result, err := generatePassphrase([]string{"a", "b"}, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result) != 6 || strings.Count(result, "_") != 1 {
+			t.Errorf("Expected two words with three digits, got %q", result)
+		}
+		if !regexp.MustCompile(`^[ab]\d*_[ab]\d*$`).MatchString(result) {
+			t.Errorf("Unexpected passphrase layout: %q", result)
+		}
+	})
+
+	t.Run("missing wordlist file", func(t *testing.T) {
+		if _, err := loadWordlist("testdata/missing.txt"); err == nil {
+			t.Error("Expected error for missing wordlist")
+		}
+	})
+}
+
+func TestRandomDecodeCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		flags    map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "ulid",
+			args:     []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+			expected: "Type: ULID\nTimestamp: 2016-07-30 23:54:10.259 (UTC)\nUnix milliseconds: 1469922850259\nRandomness: d6764c61efb99302bd5b",
+		},
+		{
+			name:     "ulid with lowercase and ambiguous letters",
+			args:     []string{"0larz3ndektsv4rrffq69g5fav"},
+			expected: "Type: ULID\nTimestamp: 2016-07-30 23:54:10.259 (UTC)\nUnix milliseconds: 1469922850259\nRandomness: d6764c61efb99302bd5b",
+		},
+		{
+			name:     "ksuid",
+			args:     []string{"0ujtsYcgvSTl8PAuAdqWYSMnLOv"},
+			expected: "Type: KSUID\nTimestamp: 2017-10-10 04:00:47.000 (UTC)\nUnix seconds: 1507608047\nPayload: b5a1cd34b5f99d1154fb6853345c9735",
+		},
+		{
+			name:     "snowflake in another timezone",
+			args:     []string{"1541815603606036480"},
+			flags:    map[string]string{"timezone": "America/New_York"},
+			expected: "Type: Snowflake\nTimestamp: 2022-06-28 12:07:40.105 (America/New_York)\nUnix milliseconds: 1656432460105\nWorker: 378\nSequence: 0",
+		},
+		{
+			name:     "snowflake with custom epoch",
+			args:     []string{"4194304"},
+			flags:    map[string]string{"type": "snowflake", "epoch": "0", "format": "2006-01-02T15:04:05.000Z07:00"},
+			expected: "Type: Snowflake\nTimestamp: 1970-01-01T00:00:00.001Z (UTC)\nUnix milliseconds: 1\nWorker: 0\nSequence: 0",
+		},
+		{
+			name:     "objectid",
+			args:     []string{"507f1f77bcf86cd799439011"},
+			expected: "Type: ObjectID\nTimestamp: 2012-10-17 21:13:27.000 (UTC)\nUnix seconds: 1350508407\nRandom: bcf86cd799\nCounter: 4427793",
+		},
+		{
+			name:     "uuid v7",
+			args:     []string{"017f22e2-79b0-7cc3-98c4-dc0c0c07398f"},
+			expected: "Type: UUID v7\nTimestamp: 2022-02-22 19:22:22.000 (UTC)",
+		},
+		{
+			name:    "uuid v4 has no timestamp",
+			args:    []string{"919108f7-52d1-4320-9bac-f847db4148a8"},
+			wantErr: true,
+		},
+		{
+			name:    "unrecognized id",
+			args:    []string{"???"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags to default values
+			decodeType = "auto"
+			decodeEpoch = twitterEpoch
+			decodeTimeFormat = "2006-01-02 15:04:05.000"
+			decodeTimezone = "UTC"
+
+			cmd := &cobra.Command{
+				Use:  "decode [id]",
+				Args: cobra.ExactArgs(1),
+				RunE: runRandomDecode,
+			}
+			cmd.Flags().StringVarP(&decodeType, "type", "t", "auto", "ID type")
+			cmd.Flags().Int64Var(&decodeEpoch, "epoch", twitterEpoch, "Snowflake epoch in Unix milliseconds")
+			cmd.Flags().StringVarP(&decodeTimeFormat, "format", "f", "2006-01-02 15:04:05.000", "Output format for embedded timestamps")
+			cmd.Flags().StringVarP(&decodeTimezone, "timezone", "z", "UTC", "Timezone for embedded timestamps")
+
+			for flag, value := range tt.flags {
+				cmd.Flags().Set(flag, value)
+			}
+
+			old := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := cmd.RunE(cmd, tt.args)
+
+			w.Close()
+			os.Stdout = old
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := strings.TrimSpace(buf.String())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if output != tt.expected {
+					t.Errorf("Expected output %q, got %q", tt.expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+
+	ulid, _ := generateULID(now)
+	if decoded, err := decodeID(ulid, "ulid", 0); err != nil || !decoded.timestamp.Equal(now) {
+		t.Errorf("ULID %s: expected %v, got %v (%v)", ulid, now, decoded.timestamp, err)
+	}
+
+	ksuid, _ := generateKSUID(now)
+	if decoded, err := decodeID(ksuid, "ksuid", 0); err != nil || decoded.timestamp.Unix() != now.Unix() {
+		t.Errorf("KSUID %s: expected %v, got %v (%v)", ksuid, now, decoded.timestamp, err)
+	}
+
+	first, _ := generateSnowflake(now, twitterEpoch, 7)
+	second, _ := generateSnowflake(now, twitterEpoch, 7)
+	if first == second {
+		t.Errorf("Expected distinct Snowflakes in the same millisecond, got %s twice", first)
+	}
+
+	objectID, _ := generateObjectID(now)
+	if decoded, err := decodeID(objectID, "objectid", 0); err != nil || decoded.timestamp.Unix() != now.Unix() {
+		t.Errorf("ObjectID %s: expected %v, got %v (%v)", objectID, now, decoded.timestamp, err)
+	}
+}
+
+func TestSeededSource(t *testing.T) {
+	draw := func(seed string) []string {
+		useSeededSource(seed)
+		defer useCryptoSource()
+
+		str, _ := generateRandomString(12)
+		num, _ := generateRandomNumber(-1000, 1000)
+		uuid, _ := generateUUID()
+		phrase, _ := generatePassphrase([]string{"a", "b", "c", "d"}, passphraseOptions{words: 4, separator: "-"})
+		ulid, _ := generateULID(time.UnixMilli(0))
+		return []string{str, strconv.FormatInt(num, 10), uuid, phrase, ulid}
+	}
+
+	first := draw("fixtures")
+	second := draw("fixtures")
+	other := draw("other")
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Expected identical values for the same seed, got %q and %q", first[i], second[i])
+		}
+		if first[i] == other[i] && i != 3 {
+			t.Errorf("Expected different values for different seeds, got %q twice", first[i])
+		}
+	}
+
+	if randomSeeded || randomSource != rand.Reader {
+		t.Error("Expected the crypto source to be restored")
+	}
+}
+
+func TestDistributions(t *testing.T) {
+	useSeededSource("distributions")
+	defer useCryptoSource()
+
+	const n = 20000
+	tests := []struct {
+		name   string
+		sample func() (float64, error)
+		mean   float64
+		stddev float64
+	}{
+		{"normal", func() (float64, error) { return sampleNormal(10, 3) }, 10, 3},
+		{"exponential", func() (float64, error) { return sampleExponential(4) }, 4, 4},
+		{"poisson small", func() (float64, error) { return samplePoisson(3) }, 3, math.Sqrt(3)},
+		{"poisson large", func() (float64, error) { return samplePoisson(400) }, 400, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, sumSq := 0.0, 0.0
+			for i := 0; i < n; i++ {
+				x, err := tt.sample()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				sum += x
+				sumSq += x * x
+			}
+			mean := sum / n
+			stddev := math.Sqrt(sumSq/n - mean*mean)
+			if math.Abs(mean-tt.mean) > 5*tt.stddev/math.Sqrt(n) {
+				t.Errorf("Expected mean near %g, got %g", tt.mean, mean)
+			}
+			if math.Abs(stddev-tt.stddev) > 0.05*tt.stddev {
+				t.Errorf("Expected stddev near %g, got %g", tt.stddev, stddev)
+			}
+		})
+	}
+}
+
+func TestNumberHelpers(t *testing.T) {
+	for input, want := range map[string]string{"-9e18": "-9000000000000000000", "1_000": "1000", "2.5": "5/2"} {
+		r, err := parseDecimal(input)
+		if err != nil || r.RatString() != want {
+			t.Errorf("parseDecimal(%q) = %v, %v; want %s", input, r, err, want)
+		}
+	}
+	if _, err := parseDecimal("ten"); err == nil {
+		t.Error("Expected error for non-numeric input")
+	}
+
+	if got := ceilRat(big.NewRat(-5, 2)); got.Int64() != -2 {
+		t.Errorf("ceilRat(-5/2) = %s, want -2", got)
+	}
+	if got := formatSample(-0.001, 2); got != "0.00" {
+		t.Errorf("formatSample(-0.001, 2) = %q, want 0.00", got)
+	}
+
+	choices, err := parseWeights([]string{"a:b:3", "c:1"})
+	if err != nil || choices[0].value != "a:b" || choices[0].weight != 3 {
+		t.Errorf("parseWeights = %v, %v", choices, err)
+	}
+	if got := pickWeighted(choices, 3.5); got != "c" {
+		t.Errorf("pickWeighted(3.5) = %q, want c", got)
+	}
+	for _, bad := range [][]string{{"a"}, {"a:-1"}, {"a:0"}} {
+		if _, err := parseWeights(bad); err == nil {
+			t.Errorf("Expected error for weights %v", bad)
+		}
+	}
+}
+
+func TestTemplateHelpers(t *testing.T) {
+	if got := jsonEscape("a\"b\\c<d>\n"); got != `a\"b\\c<d>\n` {
+		t.Errorf("jsonEscape = %q", got)
+	}
+	for input, want := range map[string]string{"name": "name", "first-name": "firstName", "user-agent": "userAgent"} {
+		if got := camelCase(input); got != want {
+			t.Errorf("camelCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	gen, err := newTemplateGenerator(`{{int 5 5}} {{float 1 2 1}} {{string 4}} {{bytes 2}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := gen.next(); err == nil {
+		t.Error("Expected a non-JSON record to be rejected")
+	}
+
+	gen, err = newTemplateGenerator(`[{{int 5 5}}, {{float 1 2 1}}, "{{string 4}}", "{{bytes 2}}", "{{firstName "fr_FR"}}", "{{regex "^q\\d$"}}"]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value, err := gen.next()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^\[5,1\.\d,"[A-Za-z0-9]{4}","[0-9a-f]{4}","[^"]+","q\d"\]$`).MatchString(value) {
+		t.Errorf("Unexpected record %s", value)
+	}
+}
+
+func TestRegexGenerator(t *testing.T) {
+	patterns := []string{
+		`^[A-Z]{3}-\d{4}$`,
+		`(?i)hello (world|there)!?`,
+		`^[^,\s]{5,8}$`,
+		`\bcat\b`,
+		`^(ab|cd)*x+$`,
+		`^\p{Greek}{3}$`,
+		`^.{2}$`,
+	}
+	for _, pattern := range patterns {
+		gen, err := newRegexGenerator(pattern, 5)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", pattern, err)
+		}
+		re := regexp.MustCompile(pattern)
+		for i := 0; i < 50; i++ {
+			value, err := gen.generate()
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", pattern, err)
+			}
+			if !re.MatchString(value) {
+				t.Errorf("%s: generated %q does not match", pattern, value)
+			}
+		}
+	}
+
+	gen, _ := newRegexGenerator(`^a*$`, 3)
+	for i := 0; i < 50; i++ {
+		if value, _ := gen.generate(); len(value) > 3 {
+			t.Errorf("Expected at most 3 repeats, got %q", value)
+		}
+	}
+
+	for _, bad := range []string{`(`, `a{2,1}`, `(?<=a)b`} {
+		if _, err := newRegexGenerator(bad, 5); err == nil {
+			t.Errorf("Expected error for pattern %q", bad)
+		}
+	}
+	if _, err := newRegexGenerator(`a`, -1); err == nil {
+		t.Error("Expected error for negative repeat limit")
+	}
+}
+
+func TestDice(t *testing.T) {
+	useSeededSource("dice")
+	defer useCryptoSource()
+
+	tests := []struct {
+		expr     string
+		min, max int
+		wantErr  bool
+	}{
+		{"d20", 1, 20, false},
+		{"4d6kh3+2", 5, 20, false},
+		{"4d6d1", 3, 18, false},
+		{"2d20kl1", 1, 20, false},
+		{"2d20dh1", 1, 20, false},
+		{"d%", 1, 100, false},
+		{"3d6 - 1d4 + 10", 9, 27, false},
+		{"3d6!", 3, 1000, false},
+		{"4d6k5", 0, 0, true},
+		{"4d6d4", 0, 0, true},
+		{"d1!", 0, 0, true},
+		{"2d", 0, 0, true},
+		{"3x6", 0, 0, true},
+		{"4d6kh3dl1", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			d, err := parseDice(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.expr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for i := 0; i < 200; i++ {
+				total, _, err := d.roll()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if total < tt.min || total > tt.max {
+					t.Fatalf("Total %d outside [%d, %d]", total, tt.min, tt.max)
+				}
+			}
+		})
+	}
+
+	d, _ := parseDice("4d6kh3")
+	_, rolls, _ := d.roll()
+	if !regexp.MustCompile(`^4d6kh3 \[[1-6 ]*\([1-6]\)[1-6 ]*\]$`).MatchString(rolls) {
+		t.Errorf("Expected one dropped die in %q", rolls)
+	}
+
+	// Explosions add dice to the pool but never to the number kept.
+	d, _ = parseDice("4d6!kh3")
+	exploded := 0
+	for i := 0; i < 500; i++ {
+		total, rolls, err := d.roll()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		dice := strings.Fields(strings.Trim(strings.TrimPrefix(rolls, "4d6!kh3 "), "[]"))
+		kept, sum := 0, 0
+		for _, die := range dice {
+			if strings.HasPrefix(die, "(") {
+				continue
+			}
+			kept++
+			v, _ := strconv.Atoi(strings.TrimSuffix(die, "!"))
+			sum += v
+		}
+		if kept != 3 || sum != total {
+			t.Fatalf("Expected 3 kept dice summing to %d, got %q", total, rolls)
+		}
+		if len(dice) > 4 {
+			exploded++
+		}
+	}
+	if exploded == 0 {
+		t.Error("Expected some rolls to explode")
+	}
+}
+
+func TestDiceDistribution(t *testing.T) {
+	tests := []struct {
+		expr  string
+		total int
+		want  float64
+	}{
+		{"2d6", 7, 6.0 / 36},
+		{"2d6+1", 8, 6.0 / 36},
+		{"1d6-1d6", 0, 6.0 / 36},
+		{"4d6kh3", 18, 21.0 / 1296},
+		{"4d6kh3", 3, 1.0 / 1296},
+		{"2d20kh1", 20, 39.0 / 400},
+		{"2d20kl1", 20, 1.0 / 400},
+		{"1d6!", 6, 0},
+		{"1d6!", 7, 1.0 / 36},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			d, err := parseDice(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			dist, _, err := d.distribution()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			sum := 0.0
+			for _, p := range dist {
+				sum += p
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("Probabilities sum to %g", sum)
+			}
+			if math.Abs(dist[tt.total]-tt.want) > 1e-12 {
+				t.Errorf("P(%d) = %g, want %g", tt.total, dist[tt.total], tt.want)
+			}
+		})
+	}
+
+	d, _ := parseDice("4d6kh3!")
+	if _, _, err := d.distribution(); err == nil {
+		t.Error("Expected an error for exploding dice with keep")
+	}
+}
+
+func TestUsableHosts(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		first string
+		size  string
+	}{
+		{"10.0.0.0/8", "10.0.0.1", "16777214"},
+		{"192.0.2.0/30", "192.0.2.1", "2"},
+		{"192.0.2.0/31", "192.0.2.0", "2"},
+		{"192.0.2.7/32", "192.0.2.7", "1"},
+		{"2001:db8::/64", "2001:db8::1", "18446744073709551615"},
+		{"2001:db8::/127", "2001:db8::", "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cidr, func(t *testing.T) {
+			prefix := netip.MustParsePrefix(tt.cidr).Masked()
+			r := usableHosts(prefix)
+			if first := offsetAddr(prefix.Addr(), r.first).String(); first != tt.first {
+				t.Errorf("Expected first host %s, got %s", tt.first, first)
+			}
+			if r.size.String() != tt.size {
+				t.Errorf("Expected %s hosts, got %s", tt.size, r.size)
+			}
+		})
+	}
+
+	for _, cidrs := range [][]string{{"10.0.0.0/8", "10.1.0.0/16"}, {"10.1.0.0/16", "10.0.0.0/8"}, {"10.0.0.0/8", "10.0.0.0/8"}} {
+		gen, err := newIPGenerator(cidrs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if gen.space.String() != "16777214" {
+			t.Errorf("Expected %v to cover 16777214 hosts, got %s", cidrs, gen.space)
+		}
+	}
+
+	gen, err := newIPGenerator([]string{"2001:db8::/120", "198.51.100.0/24"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	v4, v6 := netip.MustParsePrefix("198.51.100.0/24"), netip.MustParsePrefix("2001:db8::/120")
+	for i := 0; i < 200; i++ {
+		value, err := gen.next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		addr := netip.MustParseAddr(value)
+		if !v4.Contains(addr) && !v6.Contains(addr) {
+			t.Fatalf("Address %s outside the networks", addr)
+		}
+		if value == "198.51.100.0" || value == "198.51.100.255" || value == "2001:db8::" {
+			t.Fatalf("Generated reserved address %s", value)
+		}
+	}
+
+	ranges, err := parsePortRanges([]string{"8000-8010", "80", "8005-8020", "81"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(ranges) != "[{80 81} {8000 8020}]" {
+		t.Errorf("Unexpected merged ranges: %v", ranges)
+	}
+}
+
+func TestLoremSeeded(t *testing.T) {
+	opts := loremOptions{unit: "paragraphs", count: 2, classic: true, markup: "text"}
+	generate := func(seed string) string {
+		useSeededSource(seed)
+		defer useCryptoSource()
+		gen, err := newLoremGenerator(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, err := gen.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return text
+	}
+
+	if a, b := generate("mockup"), generate("mockup"); a != b {
+		t.Errorf("Same seed produced different text:\n%s\n---\n%s", a, b)
+	}
+	if a, b := generate("mockup"), generate("other"); a == b {
+		t.Error("Different seeds produced the same text")
+	}
+}
+
+func TestLoremMultiByteWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("école\nélan\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	gen, err := newLoremGenerator(loremOptions{unit: "sentences", count: 2, markup: "text", wordlist: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := gen.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.ValidString(text) || !strings.HasPrefix(text, "É") {
+		t.Errorf("Expected valid text starting with É, got %q", text)
+	}
+}
+
+func TestWrapWords(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"one two three", 80, "one two three"},
+		{"one two three", 7, "one two\nthree"},
+		{"one two three", 8, "one two\nthree"},
+		{"extraordinary a", 5, "extraordinary\na"},
+		{"école élan", 10, "école élan"},
+	}
+
+	for _, tt := range tests {
+		if got := wrapWords(tt.text, tt.width); got != tt.want {
+			t.Errorf("wrapWords(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
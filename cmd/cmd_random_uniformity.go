@@ -0,0 +1,515 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var randomTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run statistical uniformity tests on random output",
+	Long: `Draw a large sample from a generator, or read a byte file, and test
+whether its output looks uniform.
+
+Three tests are run on the sample's symbols (characters of strings, bytes,
+or buckets of numbers):
+  chi-square  every symbol occurs about equally often
+  monobit     ones and zeros are balanced (NIST SP 800-22 frequency test),
+              when symbols map to whole bits
+  runs        symbols above and below the middle alternate as often as
+              expected (Wald-Wolfowitz runs test)
+
+Symbols that deviate most from their expected count are listed so a bias
+in a custom --charset or a --seed stream can be located. Each test fails
+when its p-value is below --alpha; a fair generator still fails a given
+test with probability --alpha, so rerun before drawing conclusions.
+
+Generator types: string (with --alphabet, --charset, and
+--exclude-ambiguous), nanoid, number (uniform integers in [--min, --max)),
+and bytes. --require is refused: it makes the required classes more common
+on purpose, so such strings are not expected to be uniform. --file tests
+the bytes of a file instead ("-" for stdin). The command exits with an
+error when any test fails.`,
+	Args: cobra.NoArgs,
+	RunE: runRandomTest,
+}
+
+var (
+	randomTestType             string
+	randomTestLength           int
+	randomTestAlphabet         string
+	randomTestCharset          string
+	randomTestExcludeAmbiguous bool
+	randomTestRequire          []string
+	randomTestMin              string
+	randomTestMax              string
+	randomTestSeed             string
+	randomTestSamples          int
+	randomTestFile             string
+	randomTestAlpha            float64
+)
+
+// maxTestBuckets is how many buckets wide number ranges are grouped into.
+const maxTestBuckets = 64
+
+func init() {
+	flags := randomTestCmd.Flags()
+	flags.StringVarP(&randomTestType, "type", "t", "string", "Generator to test: string, nanoid, number, bytes")
+	flags.IntVarP(&randomTestLength, "length", "l", 16, "Length for strings and NanoIDs, or bytes per value")
+	flags.StringVarP(&randomTestAlphabet, "alphabet", "a", "alphanumeric", "Alphabet for strings: alphanumeric, alpha, lower, upper, numeric, hex, base32, password")
+	flags.StringVar(&randomTestCharset, "charset", "", "Custom characters for strings and NanoIDs (overrides --alphabet)")
+	flags.BoolVar(&randomTestExcludeAmbiguous, "exclude-ambiguous", false, "Exclude look-alike characters (0, O, 1, l, I, |)")
+	flags.StringSliceVar(&randomTestRequire, "require", nil, "Not supported: required classes are deliberately non-uniform")
+	flags.StringVar(&randomTestMin, "min", "0", "Minimum value (inclusive) for numbers")
+	flags.StringVar(&randomTestMax, "max", "100", "Maximum value (exclusive) for numbers")
+	flags.StringVar(&randomTestSeed, "seed", "", "Test the deterministic stream for this seed instead of the system CSPRNG")
+	flags.IntVarP(&randomTestSamples, "samples", "n", 100000, "Number of values to draw")
+	flags.StringVarP(&randomTestFile, "file", "f", "", "Test the bytes of this file (- for stdin) instead of a generator")
+	flags.Float64Var(&randomTestAlpha, "alpha", 0.001, "Significance level below which a test fails")
+	randomCmd.AddCommand(randomTestCmd)
+}
+
+func runRandomTest(cmd *cobra.Command, args []string) error {
+	if randomTestAlpha <= 0 || randomTestAlpha >= 1 {
+		return fmt.Errorf("alpha must be between 0 and 1")
+	}
+
+	var test *uniformityTest
+	var err error
+	if randomTestFile != "" {
+		test, err = testFileBytes(cmd, randomTestFile)
+	} else {
+		if randomTestSamples < 1 {
+			return fmt.Errorf("samples must be at least 1")
+		}
+		if randomTestSeed != "" {
+			useSeededSource(randomTestSeed)
+			defer useCryptoSource()
+		}
+		test, err = testGenerator(cmd, randomTestSamples)
+	}
+	if err != nil {
+		return err
+	}
+	if test.n < 2 {
+		return fmt.Errorf("sample is too small to test")
+	}
+
+	results := test.results()
+	fmt.Printf("Sample: %s\n", test.description)
+	failed := 0
+	for _, r := range results {
+		if r.skipped != "" {
+			fmt.Printf("%s: skipped (%s)\n", r.name, r.skipped)
+			continue
+		}
+		verdict := "PASS"
+		if r.p < randomTestAlpha {
+			verdict = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s: %s, p = %.4f  %s\n", r.name, r.statistic, r.p, verdict)
+	}
+
+	fmt.Println("Largest deviations:")
+	for _, d := range test.deviations(10) {
+		fmt.Printf("  %s\n", d)
+	}
+
+	if failed > 0 {
+		// A failed test is a result, not a usage mistake.
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d tests failed at alpha = %g", failed, len(results), randomTestAlpha)
+	}
+	fmt.Printf("Result: all tests passed at alpha = %g\n", randomTestAlpha)
+	return nil
+}
+
+// testGenerator draws samples values from the generator selected by --type
+// and feeds their symbols to a uniformity test.
+func testGenerator(cmd *cobra.Command, samples int) (*uniformityTest, error) {
+	source := "system CSPRNG"
+	if randomSeeded {
+		source = "seeded ChaCha8"
+	}
+
+	switch kind := strings.ToLower(randomTestType); kind {
+	case "string", "nanoid":
+		if len(randomTestRequire) > 0 {
+			return nil, fmt.Errorf("--require makes the required classes more common on purpose, so the output is not uniform; test without it")
+		}
+		var policy stringPolicy
+		var err error
+		switch {
+		case kind == "string":
+			policy, err = newStringPolicy(randomTestAlphabet, randomTestCharset, randomTestExcludeAmbiguous, nil)
+		case randomTestCharset != "":
+			policy, err = newStringPolicy("", randomTestCharset, false, nil)
+		default:
+			policy = stringPolicy{charset: []rune(nanoidAlphabet)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		length := randomTestLength
+		if kind == "nanoid" && !cmd.Flags().Changed("length") {
+			length = nanoidSize
+		}
+		if length <= 0 {
+			return nil, fmt.Errorf("length must be positive")
+		}
+		alphabet := policy.charset
+
+		index := make(map[rune]int)
+		labels := make([]string, 0, len(alphabet))
+		for _, r := range alphabet {
+			if _, ok := index[r]; !ok {
+				index[r] = len(labels)
+				labels = append(labels, strconv.QuoteRune(r))
+			}
+		}
+		test := newUniformityTest(labels, nil)
+		for i := 0; i < samples; i++ {
+			value, err := policy.generate(length)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range value {
+				symbol, ok := index[r]
+				if !ok {
+					return nil, fmt.Errorf("generated %q outside the alphabet", value)
+				}
+				test.add(symbol)
+			}
+		}
+		test.description = fmt.Sprintf("%d %ss, %d characters over a %d-character alphabet (%s)", samples, kind, test.n, len(labels), source)
+		return test, nil
+
+	case "number":
+		lo, err := parseDecimal(randomTestMin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min: %w", err)
+		}
+		hi, err := parseDecimal(randomTestMax)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max: %w", err)
+		}
+		if !lo.IsInt() || !hi.IsInt() || hi.Cmp(lo) <= 0 {
+			return nil, fmt.Errorf("number tests need integer bounds with max greater than min")
+		}
+		size := new(big.Int).Sub(hi.Num(), lo.Num())
+
+		// Group wide ranges into equal buckets; the last buckets may hold
+		// one value fewer, which their expected probabilities account for.
+		buckets := int64(maxTestBuckets)
+		if size.IsInt64() && size.Int64() < buckets {
+			buckets = size.Int64()
+		}
+		bucketStart := func(b int64) *big.Int {
+			start := new(big.Int).Mul(size, big.NewInt(b))
+			return start.Add(start, big.NewInt(buckets-1)).Div(start, big.NewInt(buckets))
+		}
+		labels := make([]string, buckets)
+		probs := make([]float64, buckets)
+		for b := int64(0); b < buckets; b++ {
+			from, to := bucketStart(b), bucketStart(b+1)
+			width := new(big.Int).Sub(to, from)
+			probs[b], _ = new(big.Rat).SetFrac(width, size).Float64()
+			from.Add(from, lo.Num())
+			labels[b] = from.String()
+			if width.Cmp(big.NewInt(1)) > 0 {
+				labels[b] = fmt.Sprintf("[%s, %s)", from, to.Add(to, lo.Num()))
+			}
+		}
+
+		test := newUniformityTest(labels, probs)
+		for i := 0; i < samples; i++ {
+			n, err := generateRandomBigInt(lo.Num(), hi.Num())
+			if err != nil {
+				return nil, err
+			}
+			// bucket = floor((n - min) * buckets / size)
+			offset := n.Sub(n, lo.Num())
+			bucket := offset.Mul(offset, big.NewInt(buckets)).Div(offset, size)
+			test.add(int(bucket.Int64()))
+		}
+		test.description = fmt.Sprintf("%d numbers in [%s, %s) in %d buckets (%s)", samples, randomTestMin, randomTestMax, buckets, source)
+		return test, nil
+
+	case "bytes":
+		if randomTestLength <= 0 {
+			return nil, fmt.Errorf("length must be positive")
+		}
+		test := newByteTest()
+		b := make([]byte, randomTestLength)
+		for i := 0; i < samples; i++ {
+			if err := randomBytes(b); err != nil {
+				return nil, err
+			}
+			for _, c := range b {
+				test.add(int(c))
+			}
+		}
+		test.description = fmt.Sprintf("%d values of %d bytes (%s)", samples, randomTestLength, source)
+		return test, nil
+
+	default:
+		return nil, fmt.Errorf("cannot test random type: %s (supported: string, nanoid, number, bytes)", randomTestType)
+	}
+}
+
+// testFileBytes streams the bytes of a file, or stdin for "-".
+func testFileBytes(cmd *cobra.Command, path string) (*uniformityTest, error) {
+	var r io.Reader = cmd.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	test := newByteTest()
+	br := bufio.NewReader(r)
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		test.add(int(c))
+	}
+	test.description = fmt.Sprintf("%d bytes from %s", test.n, path)
+	return test, nil
+}
+
+func newByteTest() *uniformityTest {
+	labels := make([]string, 256)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("0x%02x", i)
+	}
+	return newUniformityTest(labels, nil)
+}
+
+// uniformityTest accumulates the statistics of a stream of symbols
+// 0..len(labels)-1 expected with the given probabilities.
+type uniformityTest struct {
+	description string
+	labels      []string
+	probs       []float64
+	observed    []int
+	n           int
+
+	// bitsPerSymbol is set when the symbols are equally likely and their
+	// count is a power of two, so each symbol is that many fair bits.
+	bitsPerSymbol int
+
+	// runs counts maximal runs in the binary sequence: the bits, or
+	// whether each symbol is in the upper half of the categories.
+	runs     int
+	last     int
+	high     int
+	low      int
+	halfMark int
+}
+
+// newUniformityTest expects symbols with probabilities probs, or equally
+// likely symbols when probs is nil.
+func newUniformityTest(labels []string, probs []float64) *uniformityTest {
+	m := len(labels)
+	t := &uniformityTest{labels: labels, probs: probs, observed: make([]int, m), last: -1, halfMark: m / 2}
+	if probs == nil {
+		t.probs = make([]float64, m)
+		for i := range t.probs {
+			t.probs[i] = 1 / float64(m)
+		}
+		if m > 1 && m&(m-1) == 0 {
+			t.bitsPerSymbol = bits.TrailingZeros(uint(m))
+		}
+	}
+	return t
+}
+
+func (t *uniformityTest) add(symbol int) {
+	t.observed[symbol]++
+	t.n++
+	if t.bitsPerSymbol > 0 {
+		for k := t.bitsPerSymbol - 1; k >= 0; k-- {
+			t.addBinary(symbol >> k & 1)
+		}
+		return
+	}
+	if symbol >= t.halfMark {
+		t.addBinary(1)
+	} else {
+		t.addBinary(0)
+	}
+}
+
+func (t *uniformityTest) addBinary(b int) {
+	if b == 1 {
+		t.high++
+	} else {
+		t.low++
+	}
+	if b != t.last {
+		t.runs++
+		t.last = b
+	}
+}
+
+// testResult is the outcome of one statistical test.
+type testResult struct {
+	name      string
+	statistic string
+	p         float64
+	skipped   string
+}
+
+func (t *uniformityTest) results() []testResult {
+	// Chi-square goodness of fit against the expected probabilities.
+	chi2 := 0.0
+	for i, o := range t.observed {
+		e := float64(t.n) * t.probs[i]
+		chi2 += (float64(o) - e) * (float64(o) - e) / e
+	}
+	df := len(t.observed) - 1
+	chiSquare := testResult{
+		name:      "Chi-square",
+		statistic: fmt.Sprintf("X^2 = %.2f, df = %d", chi2, df),
+		p:         upperIncompleteGamma(float64(df)/2, chi2/2),
+	}
+	if minExpected := float64(t.n) * minFloat(t.probs); minExpected < 5 {
+		chiSquare = testResult{name: "Chi-square", skipped: "expected counts below 5; draw more samples"}
+	}
+
+	// Monobit: the bit sum of n fair bits is approximately normal.
+	monobit := testResult{name: "Monobit", skipped: "symbols are not whole bits"}
+	if t.bitsPerSymbol > 0 {
+		total := t.high + t.low
+		sObs := math.Abs(float64(t.high-t.low)) / math.Sqrt(float64(total))
+		monobit = testResult{
+			name:      "Monobit",
+			statistic: fmt.Sprintf("%d ones, %d zeros", t.high, t.low),
+			p:         math.Erfc(sObs / math.Sqrt2),
+		}
+	}
+
+	// Wald-Wolfowitz runs test on the binary sequence.
+	runs := testResult{name: "Runs", skipped: "the sequence never changes"}
+	n1, n2 := float64(t.high), float64(t.low)
+	if n1 > 0 && n2 > 0 {
+		total := n1 + n2
+		mean := 2*n1*n2/total + 1
+		variance := (mean - 1) * (mean - 2) / (total - 1)
+		z := (float64(t.runs) - mean) / math.Sqrt(variance)
+		runs = testResult{
+			name:      "Runs",
+			statistic: fmt.Sprintf("%d runs, expected %.1f", t.runs, mean),
+			p:         math.Erfc(math.Abs(z) / math.Sqrt2),
+		}
+	}
+	return []testResult{chiSquare, monobit, runs}
+}
+
+// deviations describes the symbols whose counts are furthest from their
+// expected counts, measured in standard deviations.
+func (t *uniformityTest) deviations(limit int) []string {
+	type deviation struct {
+		symbol int
+		z      float64
+	}
+	var devs []deviation
+	for i, o := range t.observed {
+		p := t.probs[i]
+		e := float64(t.n) * p
+		sd := math.Sqrt(float64(t.n) * p * (1 - p))
+		z := 0.0
+		if sd > 0 {
+			z = (float64(o) - e) / sd
+		}
+		devs = append(devs, deviation{i, z})
+	}
+	sort.SliceStable(devs, func(a, b int) bool { return math.Abs(devs[a].z) > math.Abs(devs[b].z) })
+
+	width := 0
+	for _, label := range t.labels {
+		width = max(width, len(label))
+	}
+	var lines []string
+	for _, d := range devs[:min(limit, len(devs))] {
+		e := float64(t.n) * t.probs[d.symbol]
+		lines = append(lines, fmt.Sprintf("%-*s  %d observed, %.1f expected (%+.2f%%, %+.2f sd)",
+			width, t.labels[d.symbol], t.observed[d.symbol], e, 100*(float64(t.observed[d.symbol])-e)/e, d.z))
+	}
+	return lines
+}
+
+func minFloat(values []float64) float64 {
+	m := math.Inf(1)
+	for _, v := range values {
+		m = math.Min(m, v)
+	}
+	return m
+}
+
+// upperIncompleteGamma is the regularized upper incomplete gamma function
+// Q(a, x), the chi-square survival function for a = df/2 and x = X^2/2. It
+// uses the series for small x and a continued fraction otherwise.
+func upperIncompleteGamma(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	// Modified Lentz's method for the continued fraction.
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 1000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return prefix * h
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpperIncompleteGamma(t *testing.T) {
+	// Chi-square critical values: P(X^2 > x) for df degrees of freedom.
+	tests := []struct {
+		df   int
+		x    float64
+		want float64
+	}{
+		{1, 3.841, 0.05},
+		{1, 10.828, 0.001},
+		{10, 18.307, 0.05},
+		{63, 82.529, 0.05},
+		{255, 293.248, 0.05},
+		{2, 0, 1},
+	}
+
+	for _, tt := range tests {
+		got := upperIncompleteGamma(float64(tt.df)/2, tt.x/2)
+		if math.Abs(got-tt.want) > 0.0005 {
+			t.Errorf("Q(df=%d, x=%g) = %.5f, want %.4f", tt.df, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestUniformityTest(t *testing.T) {
+	useSeededSource("uniformity")
+	defer useCryptoSource()
+
+	failures := func(test *uniformityTest) []string {
+		t.Helper()
+		var failed []string
+		for _, r := range test.results() {
+			if r.skipped == "" && r.p < 0.001 {
+				failed = append(failed, r.name)
+			}
+		}
+		return failed
+	}
+
+	fair := newByteTest()
+	b := make([]byte, 50000)
+	if err := randomBytes(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range b {
+		fair.add(int(c))
+	}
+	if failed := failures(fair); len(failed) > 0 {
+		t.Errorf("Random bytes failed %v", failed)
+	}
+
+	// Alternating symbols are perfectly balanced but far too regular.
+	alternating := newUniformityTest([]string{"a", "b"}, nil)
+	for i := 0; i < 10000; i++ {
+		alternating.add(i % 2)
+	}
+	if failed := failures(alternating); len(failed) != 1 || failed[0] != "Runs" {
+		t.Errorf("Expected only the runs test to fail for alternating symbols, got %v", failed)
+	}
+
+	// Symbol 0 appears twice as often as it should.
+	biased := newUniformityTest([]string{"a", "b", "c"}, nil)
+	for i := 0; i < 30000; i++ {
+		n, err := randomIndex(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		biased.add(max(n-1, 0))
+	}
+	if failed := failures(biased); len(failed) == 0 || failed[0] != "Chi-square" {
+		t.Errorf("Expected the chi-square test to fail for a biased stream, got %v", failed)
+	}
+	if got := biased.deviations(1)[0]; !strings.HasPrefix(got, "a ") {
+		t.Errorf("Expected symbol a to deviate most, got %q", got)
+	}
+}
+
+func TestRandomTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	zeros := filepath.Join(dir, "zeros.bin")
+	if err := os.WriteFile(zeros, make([]byte, 4096), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		typ     string
+		file    string
+		require []string
+		wantErr bool
+	}{
+		{"string", "string", "", nil, false},
+		{"nanoid", "nanoid", "", nil, false},
+		{"number", "number", "", nil, false},
+		{"bytes", "bytes", "", nil, false},
+		{"zero file", "string", zeros, nil, true},
+		{"unsupported type", "uuid", "", nil, true},
+		{"required classes", "string", "", []string{"symbol", "digit"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			randomTestType = tt.typ
+			randomTestLength = 16
+			randomTestAlphabet = "alphanumeric"
+			randomTestCharset = ""
+			randomTestExcludeAmbiguous = false
+			randomTestRequire = tt.require
+			randomTestMin, randomTestMax = "0", "100"
+			randomTestSeed = "uniformity"
+			randomTestSamples = 2000
+			randomTestFile = tt.file
+			randomTestAlpha = 0.0001
+
+			err := randomTestCmd.RunE(randomTestCmd, nil)
+			if tt.wantErr && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRandomTestKeepsRandomFlags(t *testing.T) {
+	for _, name := range []string{"type", "length", "alphabet", "charset", "min", "max", "seed"} {
+		if randomTestCmd.Flags().Lookup(name).Value == randomCmd.Flags().Lookup(name).Value {
+			t.Errorf("--%s is shared between random and random test", name)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -12,64 +11,178 @@ import (
 var timeCmd = &cobra.Command{
 	Use:   "time [timestamp]",
 	Short: "Convert and format timestamps",
-	Long:  `Convert between Unix timestamps and human-readable dates, or get current time.`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runTime,
+	Long: `Convert between Unix timestamps and human-readable dates, or get current time.
+
+Dates are recognised in many common formats: RFC 3339 and ISO 8601 (with
+or without offsets and fractional seconds), RFC 1123 HTTP dates, RFC 2822
+email dates, RFC 850, ANSI C and Unix date output, Apache Common Log Format
+(with or without brackets), syslog (RFC 3164, using the current year), and
+plain dates and times. The layout that matched is printed. --input-format
+restricts parsing to a catalog key (rfc3339, rfc1123, clf, syslog, ...) or a
+Go reference layout such as "02.01.2006 15:04"; repeat it to try several.
+Offsets and zones in the input are honoured; times without one are read
+in --from-timezone (default UTC), so "2024-01-01 09:00" with
+--from-timezone America/New_York is 14:00 UTC. Zone abbreviations are
+understood for UTC, GMT, and the common US and European zones (EST, PDT,
+CET, BST, ...); others are rejected rather than read as UTC.
+
+--timezone selects the zone the result is shown in; repeat it or pass a
+comma-separated list to show the same instant in several zones at once.
+
+Relative expressions are understood too: "now", "3 days ago", "in 2 hours",
+"a week from now", "tomorrow noon", "yesterday at 5pm", "next friday 17:00",
+"last monday", "next month", "start of week", "end of last month". Named
+days mean midnight unless a time is given; "friday" is the coming one (today
+included), "next friday" the first after today. Weeks start on Monday.
+They are evaluated in --from-timezone when it is given, otherwise in the
+first --timezone, relative to --now (an epoch or a date in any catalog
+layout, whatever --input-format and --unit say) or the current time.
+
+Numbers are Unix epochs. Their precision is detected by magnitude, so
+1700000000, 1700000000000, and 1700000000000000000 are the same instant in
+seconds, milliseconds, and nanoseconds; --unit s|ms|us|ns overrides the
+guess, and fractional and scientific notation values are accepted. The
+result is printed at every precision. With --timestamp only the seconds are
+printed, or the --unit precision when one is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTime,
 }
 
 var (
 	timeFormat   string
 	toTimestamp  bool
-	timeTimezone string
+	timeTimezone []string
+	timeFrom     string
+	timeInput    []string
+	timeUnit     string
+	timeNow      string
 )
 
 func init() {
 	timeCmd.Flags().StringVarP(&timeFormat, "format", "f", "2006-01-02 15:04:05", "Output format for time")
 	timeCmd.Flags().BoolVarP(&toTimestamp, "timestamp", "t", false, "Convert to Unix timestamp instead")
-	timeCmd.Flags().StringVarP(&timeTimezone, "timezone", "z", "UTC", "Timezone (UTC, Local, or IANA name)")
+	timeCmd.Flags().StringSliceVarP(&timeTimezone, "timezone", "z", []string{"UTC"}, "Output timezones (UTC, Local, or IANA name; repeatable)")
+	timeCmd.Flags().StringVar(&timeFrom, "from-timezone", "UTC", "Timezone of input without an offset (UTC, Local, or IANA name)")
+	timeCmd.Flags().StringArrayVarP(&timeInput, "input-format", "i", nil, "Input layout: a catalog key or Go reference layout (repeatable)")
+	timeCmd.Flags().StringVarP(&timeUnit, "unit", "u", "auto", "Epoch precision for numeric input: auto, s, ms, us, ns")
+	timeCmd.Flags().StringVar(&timeNow, "now", "", "Reference time for relative expressions (default: the current time)")
 	rootCmd.AddCommand(timeCmd)
 }
 
 func runTime(cmd *cobra.Command, args []string) error {
 	var targetTime time.Time
+	var inputFormat string
 	var err error
 
-	location, err := parseTimezone(timeTimezone)
+	if len(timeTimezone) == 0 {
+		return fmt.Errorf("at least one timezone is required")
+	}
+	locations := make([]*time.Location, len(timeTimezone))
+	for i, tz := range timeTimezone {
+		if locations[i], err = parseTimezone(strings.TrimSpace(tz)); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+	location := locations[0]
+	fromLocation, err := parseTimezone(timeFrom)
 	if err != nil {
-		return fmt.Errorf("invalid timezone: %w", err)
+		return fmt.Errorf("invalid input timezone: %w", err)
+	}
+	if !strings.EqualFold(timeUnit, "auto") {
+		if _, err := lookupEpochUnit(timeUnit); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if timeNow != "" {
+		if now, err = parseReferenceTime(timeNow, now, fromLocation); err != nil {
+			return fmt.Errorf("invalid --now: %w", err)
+		}
 	}
 
 	if len(args) == 0 {
-		targetTime = time.Now().In(location)
+		targetTime = now.In(location)
 	} else {
-		input := args[0]
-
-		if timestamp, err := strconv.ParseInt(input, 10, 64); err == nil {
-			targetTime = time.Unix(timestamp, 0).In(location)
-		} else {
-			targetTime, err = time.Parse("2006-01-02 15:04:05", input)
+		// Relative expressions are evaluated in the zone the input is
+		// given in, or else the one the result is shown in.
+		naturalLocation := location
+		if cmd.Flags().Changed("from-timezone") {
+			naturalLocation = fromLocation
+		}
+		natural := false
+		if len(timeInput) == 0 {
+			targetTime, natural, err = parseNaturalTime(args[0], now.In(naturalLocation))
 			if err != nil {
-				targetTime, err = time.Parse("2006-01-02", input)
-				if err != nil {
-					return fmt.Errorf("failed to parse time: %w", err)
-				}
+				return fmt.Errorf("failed to parse time: %w", err)
 			}
-			targetTime = targetTime.In(location)
 		}
+		if natural {
+			inputFormat = fmt.Sprintf("natural language, relative to %s", now.In(naturalLocation).Format(time.RFC3339))
+		} else if targetTime, inputFormat, err = parseAbsoluteTime(args[0], now, fromLocation); err != nil {
+			return err
+		}
+		targetTime = targetTime.In(location)
 	}
 
 	if toTimestamp {
-		fmt.Printf("Unix timestamp: %d\n", targetTime.Unix())
+		unit := epochUnits[0]
+		if !strings.EqualFold(timeUnit, "auto") {
+			unit, _ = lookupEpochUnit(timeUnit)
+		}
+		if unit.key == "s" {
+			fmt.Printf("Unix timestamp: %d\n", targetTime.Unix())
+		} else {
+			fmt.Printf("Unix %s: %s\n", unit.name, unixIn(targetTime, unit))
+		}
 	} else {
-		fmt.Printf("Formatted time (%s): %s\n", timeTimezone, targetTime.Format(timeFormat))
+		for i, loc := range locations {
+			fmt.Printf("Formatted time (%s): %s\n", strings.TrimSpace(timeTimezone[i]), formatTime(targetTime, loc, timeFormat))
+		}
 		fmt.Printf("Unix timestamp: %d\n", targetTime.Unix())
-		fmt.Printf("ISO 8601: %s\n", targetTime.Format(time.RFC3339))
+		fmt.Printf("ISO 8601: %s\n", targetTime.Format(time.RFC3339Nano))
 		fmt.Printf("Weekday: %s\n", targetTime.Weekday())
+		for _, u := range epochUnits[1:] {
+			fmt.Printf("Unix %s: %s\n", u.name, unixIn(targetTime, u))
+		}
+		if inputFormat != "" {
+			fmt.Printf("Input format: %s\n", inputFormat)
+		}
 	}
 
 	return nil
 }
 
+// parseAbsoluteTime parses a Unix epoch, or a date in the --input-format
+// layouts or the layout catalog, and describes how it was read.
+func parseAbsoluteTime(input string, now time.Time, from *time.Location) (time.Time, string, error) {
+	if len(timeInput) == 0 {
+		t, inputFormat, isEpoch, err := parseEpoch(input, timeUnit)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+		if isEpoch {
+			return t, inputFormat, nil
+		}
+	}
+	t, inputFormat, err := parseTimeString(input, timeInput, now, from)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("failed to parse time: %w", err)
+	}
+	return t, inputFormat, nil
+}
+
+// parseReferenceTime parses --now as an epoch of any precision or a date in
+// any catalog layout. --input-format and --unit describe the argument only.
+func parseReferenceTime(input string, now time.Time, from *time.Location) (time.Time, error) {
+	t, _, isEpoch, err := parseEpoch(input, "auto")
+	if err != nil || isEpoch {
+		return t, err
+	}
+	t, _, err = parseTimeString(input, nil, now, from)
+	return t, err
+}
+
 func parseTimezone(tz string) (*time.Location, error) {
 	switch strings.ToLower(tz) {
 	case "utc":
@@ -80,3 +193,9 @@ func parseTimezone(tz string) (*time.Location, error) {
 		return time.LoadLocation(tz)
 	}
 }
+
+// formatTime renders t in loc using a Go reference layout. Other commands
+// use it so dates look the same everywhere in plz.
+func formatTime(t time.Time, loc *time.Location, layout string) string {
+	return t.In(loc).Format(layout)
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// naturalUnits maps the unit words of relative expressions to their
+// canonical names.
+var naturalUnits = map[string]string{
+	"second": "second", "seconds": "second", "sec": "second", "secs": "second",
+	"minute": "minute", "minutes": "minute", "min": "minute", "mins": "minute",
+	"hour": "hour", "hours": "hour", "hr": "hour", "hrs": "hour",
+	"day": "day", "days": "day",
+	"week": "week", "weeks": "week", "wk": "week", "wks": "week",
+	"month": "month", "months": "month",
+	"year": "year", "years": "year", "yr": "year", "yrs": "year",
+}
+
+// naturalWeekdays maps weekday names and abbreviations to weekdays.
+var naturalWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// clockPattern matches times of day such as 17:00, 09:30:15, 5pm, and
+// 5:30am. A bare number is a count, not a time.
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?(am|pm)?$`)
+
+// naturalParser evaluates a relative time expression clause by clause.
+type naturalParser struct {
+	t time.Time
+	// dated is set once a day is named, so the time of day defaults to
+	// midnight instead of the current time.
+	dated bool
+	// clock is the hour, minute, and second given in the expression, if
+	// any.
+	clock *[3]int
+	// outOfRange is set when a count is too large for any valid date.
+	outOfRange bool
+}
+
+// maxNaturalCount bounds the counts of relative offsets. Larger ones reach
+// past the year 9999 in any unit, and could overflow the arithmetic.
+const maxNaturalCount = 1e12
+
+// parseNaturalTime evaluates expressions such as "now", "3 days ago",
+// "in 2 hours", "tomorrow noon", "next friday 17:00", "last monday at
+// 9:30am", and "start of next month" relative to now, in now's location.
+// Named days resolve to midnight unless a time is given; weeks start on
+// Monday. It reports false when input is not such an expression, and an
+// error when the result falls outside the years 1 to 9999.
+func parseNaturalTime(input string, now time.Time) (time.Time, bool, error) {
+	tokens := strings.Fields(strings.ToLower(strings.ReplaceAll(input, ",", " ")))
+	if len(tokens) == 0 {
+		return time.Time{}, false, nil
+	}
+
+	p := &naturalParser{t: now}
+	meaningful := false
+	for len(tokens) > 0 {
+		n := p.clause(tokens)
+		if n == 0 {
+			return time.Time{}, false, nil
+		}
+		if !naturalFiller(tokens[:n]) {
+			meaningful = true
+		}
+		tokens = tokens[n:]
+	}
+	// "in", "at", "later", and "from now" only decorate other clauses.
+	if !meaningful {
+		return time.Time{}, false, nil
+	}
+
+	t := p.t
+	switch {
+	case p.clock != nil:
+		t = time.Date(t.Year(), t.Month(), t.Day(), p.clock[0], p.clock[1], p.clock[2], 0, t.Location())
+	case p.dated:
+		t = startOfDay(t)
+	}
+	if p.outOfRange || t.Unix() < minEpochSeconds || t.Unix() > maxEpochSeconds {
+		return time.Time{}, true, fmt.Errorf("%q is outside the years 1 to 9999", input)
+	}
+	return t, true, nil
+}
+
+// clause applies the clause at the start of tokens and returns how many
+// tokens it used, or 0 when they are not understood.
+func (p *naturalParser) clause(tokens []string) int {
+	word := tokens[0]
+	next := ""
+	if len(tokens) > 1 {
+		next = tokens[1]
+	}
+
+	switch word {
+	case "now", "at", "in", "later":
+		return 1
+	case "from":
+		if next == "now" {
+			return 2
+		}
+		return 0
+	case "today":
+		p.dated = true
+		return 1
+	case "tomorrow":
+		p.t, p.dated = p.t.AddDate(0, 0, 1), true
+		return 1
+	case "yesterday":
+		p.t, p.dated = p.t.AddDate(0, 0, -1), true
+		return 1
+	case "noon":
+		return p.setClock(12, 0, 0)
+	case "midnight":
+		return p.setClock(0, 0, 0)
+	case "this", "next", "last":
+		if wd, ok := naturalWeekdays[next]; ok {
+			p.weekday(wd, word)
+			return 2
+		}
+		if unit, ok := naturalUnits[next]; ok {
+			p.t = addUnits(p.t, unit, relativeShift(word))
+			return 2
+		}
+		return 0
+	case "start", "beginning", "end":
+		return p.boundary(tokens)
+	}
+
+	if wd, ok := naturalWeekdays[word]; ok {
+		p.weekday(wd, "this")
+		return 1
+	}
+
+	// "5 pm"
+	if next == "am" || next == "pm" {
+		if n := p.parseClock(word + next); n > 0 {
+			return 2
+		}
+		return 0
+	}
+
+	// "3 days", "a week", "-2 hours", optionally followed by "ago".
+	count, err := strconv.Atoi(word)
+	if word == "a" || word == "an" {
+		count, err = 1, nil
+	}
+	if err == nil {
+		unit, ok := naturalUnits[next]
+		if !ok {
+			return 0
+		}
+		used := 2
+		if len(tokens) > 2 && tokens[2] == "ago" {
+			count, used = -count, 3
+		}
+		if count > maxNaturalCount || count < -maxNaturalCount {
+			p.outOfRange = true
+			return used
+		}
+		p.t = addUnits(p.t, unit, count)
+		return used
+	}
+
+	return p.parseClock(word)
+}
+
+// naturalFiller reports whether a clause is a connecting word that says
+// nothing on its own.
+func naturalFiller(clause []string) bool {
+	switch clause[0] {
+	case "at", "in", "later", "from":
+		return true
+	}
+	return false
+}
+
+// parseClock sets the time of day from a clock token.
+func (p *naturalParser) parseClock(token string) int {
+	m := clockPattern.FindStringSubmatch(token)
+	if m == nil || (m[2] == "" && m[4] == "") {
+		return 0
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	if m[4] != "" {
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour %= 12
+		if m[4] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0
+	}
+	return p.setClock(hour, minute, second)
+}
+
+func (p *naturalParser) setClock(hour, minute, second int) int {
+	p.clock = &[3]int{hour, minute, second}
+	return 1
+}
+
+// weekday moves to weekday wd: the coming one (today included) for "this",
+// the first one after today for "next", and the last one before today for
+// "last".
+func (p *naturalParser) weekday(wd time.Weekday, which string) {
+	current := p.t.Weekday()
+	var days int
+	switch which {
+	case "last":
+		days = -((int(current)-int(wd)+6)%7 + 1)
+	case "next":
+		days = (int(wd)-int(current)+6)%7 + 1
+	default:
+		days = (int(wd) - int(current) + 7) % 7
+	}
+	p.t, p.dated = p.t.AddDate(0, 0, days), true
+}
+
+// boundary handles "start of [the] [this|next|last] UNIT" and the same with
+// "beginning" or "end"; the end is the last nanosecond of the period.
+func (p *naturalParser) boundary(tokens []string) int {
+	used := 1
+	if used >= len(tokens) || tokens[used] != "of" {
+		return 0
+	}
+	used++
+	if used < len(tokens) && tokens[used] == "the" {
+		used++
+	}
+	shift := 0
+	if used < len(tokens) {
+		switch tokens[used] {
+		case "this", "next", "last":
+			shift = relativeShift(tokens[used])
+			used++
+		}
+	}
+	if used >= len(tokens) {
+		return 0
+	}
+	unit, ok := naturalUnits[tokens[used]]
+	if !ok || unit == "second" || unit == "minute" || unit == "hour" {
+		return 0
+	}
+
+	start := startOf(addUnits(p.t, unit, shift), unit)
+	if tokens[0] == "end" {
+		p.t, p.dated = addUnits(start, unit, 1).Add(-time.Nanosecond), false
+	} else {
+		p.t, p.dated = start, true
+	}
+	return used + 1
+}
+
+func relativeShift(which string) int {
+	switch which {
+	case "next":
+		return 1
+	case "last":
+		return -1
+	}
+	return 0
+}
+
+// addUnits adds n units to t. Seconds, minutes, and hours are added in
+// whole seconds, so counts beyond a time.Duration's 292 years don't wrap.
+// Days and longer keep the time of day across DST changes, and months keep
+// the day of the month where it exists (Jan 31 plus a month is the last day
+// of February).
+func addUnits(t time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "second":
+		return addSeconds(t, int64(n))
+	case "minute":
+		return addSeconds(t, int64(n)*60)
+	case "hour":
+		return addSeconds(t, int64(n)*3600)
+	case "day":
+		return t.AddDate(0, 0, n)
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "year":
+		n *= 12
+	}
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+func addSeconds(t time.Time, seconds int64) time.Time {
+	return time.Unix(t.Unix()+seconds, int64(t.Nanosecond())).In(t.Location())
+}
+
+// startOf returns the start of the day, Monday-based week, month, or year
+// containing t.
+func startOf(t time.Time, unit string) time.Time {
+	day := startOfDay(t)
+	switch unit {
+	case "week":
+		return day.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case "month":
+		return day.AddDate(0, 0, 1-t.Day())
+	case "year":
+		return day.AddDate(0, 0, 1-t.YearDay())
+	}
+	return day
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
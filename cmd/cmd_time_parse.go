@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// epochUnit is a precision of Unix epoch timestamps.
+type epochUnit struct {
+	key   string
+	name  string
+	nanos int64
+	// below is the magnitude up to which auto-detection picks this unit:
+	// every unit covers dates up to the year 5138.
+	below float64
+}
+
+var epochUnits = []epochUnit{
+	{key: "s", name: "seconds", nanos: 1e9, below: 1e11},
+	{key: "ms", name: "milliseconds", nanos: 1e6, below: 1e14},
+	{key: "us", name: "microseconds", nanos: 1e3, below: 1e17},
+	{key: "ns", name: "nanoseconds", nanos: 1},
+}
+
+// lookupEpochUnit returns the epoch unit with the given key.
+func lookupEpochUnit(key string) (epochUnit, error) {
+	for _, u := range epochUnits {
+		if strings.EqualFold(u.key, key) {
+			return u, nil
+		}
+	}
+	return epochUnit{}, fmt.Errorf("unsupported unit: %s (supported: auto, s, ms, us, ns)", key)
+}
+
+// epochPattern matches numeric input such as 1700000000, 1700000000.25, or
+// 1.7e12, which is read as a Unix epoch rather than a date.
+var epochPattern = regexp.MustCompile(`^[+-]?[0-9][0-9_]*(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// parseEpoch reads numeric input as a Unix epoch in unit ("s", "ms", "us",
+// or "ns"), or in the unit its magnitude suggests when unit is "auto". It
+// reports false when input is not a number.
+func parseEpoch(input, unit string) (time.Time, string, bool, error) {
+	input = strings.TrimSpace(input)
+	if !epochPattern.MatchString(input) {
+		return time.Time{}, "", false, nil
+	}
+	value, err := parseDecimal(input)
+	if err != nil {
+		return time.Time{}, "", true, err
+	}
+
+	var chosen epochUnit
+	how := "detected by magnitude"
+	switch {
+	case strings.EqualFold(unit, "auto"):
+		magnitude, _ := new(big.Rat).Abs(value).Float64()
+		for _, u := range epochUnits {
+			chosen = u
+			if magnitude < u.below {
+				break
+			}
+		}
+	default:
+		if chosen, err = lookupEpochUnit(unit); err != nil {
+			return time.Time{}, "", true, err
+		}
+		how = "from --unit"
+	}
+
+	// Split the exact nanosecond count into seconds and a non-negative
+	// remainder, truncating anything finer than a nanosecond.
+	nanos := new(big.Rat).Mul(value, new(big.Rat).SetInt64(chosen.nanos))
+	total := new(big.Int).Quo(nanos.Num(), nanos.Denom())
+	sec, nsec := new(big.Int).DivMod(total, big.NewInt(1e9), new(big.Int))
+	if !sec.IsInt64() || sec.Int64() < minEpochSeconds || sec.Int64() > maxEpochSeconds {
+		return time.Time{}, "", true, fmt.Errorf("%s Unix %s is out of range", input, chosen.name)
+	}
+	return time.Unix(sec.Int64(), nsec.Int64()), fmt.Sprintf("Unix %s (%s)", chosen.name, how), true, nil
+}
+
+// Epochs are limited to the years 1 to 9999, which format sensibly.
+const (
+	minEpochSeconds = -62135596800 // 0001-01-01T00:00:00Z
+	maxEpochSeconds = 253402300799 // 9999-12-31T23:59:59Z
+)
+
+// unixIn returns t as a count of units since the Unix epoch, truncated
+// toward negative infinity.
+func unixIn(t time.Time, u epochUnit) *big.Int {
+	n := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(1e9))
+	n.Add(n, big.NewInt(int64(t.Nanosecond())))
+	return n.Div(n, big.NewInt(u.nanos))
+}
+
+// timeLayout is a named Go reference layout tried when parsing time input.
+type timeLayout struct {
+	key    string
+	name   string
+	layout string
+	// noYear layouts leave the year out, as syslog does; the year is taken
+	// from the current time.
+	noYear bool
+}
+
+// timeLayouts is the catalog of layouts the time parser tries in order.
+// Fractional seconds are accepted after the seconds of every layout, so
+// "2024-03-05T10:00:00.123Z" matches RFC 3339.
+var timeLayouts = []timeLayout{
+	{key: "rfc3339", name: "RFC 3339", layout: time.RFC3339},
+	{key: "iso8601", name: "ISO 8601 (basic offset)", layout: "2006-01-02T15:04:05Z0700"},
+	{key: "iso8601-local", name: "ISO 8601 (no offset)", layout: "2006-01-02T15:04:05"},
+	{key: "iso8601-minutes", name: "ISO 8601 (minutes)", layout: "2006-01-02T15:04Z07:00"},
+	{key: "iso8601-local-minutes", name: "ISO 8601 (minutes, no offset)", layout: "2006-01-02T15:04"},
+	{key: "iso8601-basic", name: "ISO 8601 (basic)", layout: "20060102T150405Z0700"},
+	{key: "datetime-offset", name: "date and time with offset", layout: "2006-01-02 15:04:05Z07:00"},
+	{key: "go", name: "Go time.String", layout: "2006-01-02 15:04:05 -0700 MST"},
+	{key: "datetime-numeric-offset", name: "date and time with numeric offset", layout: "2006-01-02 15:04:05 -0700"},
+	{key: "datetime-zone", name: "date and time with zone", layout: "2006-01-02 15:04:05 MST"},
+	{key: "datetime", name: "date and time", layout: "2006-01-02 15:04:05"},
+	{key: "datetime-minutes", name: "date and time (minutes)", layout: "2006-01-02 15:04"},
+	{key: "date", name: "date", layout: "2006-01-02"},
+	{key: "slash-datetime", name: "date and time with slashes", layout: "2006/01/02 15:04:05"},
+	{key: "slash-date", name: "date with slashes", layout: "2006/01/02"},
+	{key: "rfc1123", name: "RFC 1123 (HTTP date)", layout: time.RFC1123},
+	{key: "rfc1123z", name: "RFC 1123 with numeric offset", layout: time.RFC1123Z},
+	{key: "rfc850", name: "RFC 850", layout: time.RFC850},
+	{key: "rfc822", name: "RFC 822", layout: time.RFC822},
+	{key: "rfc822z", name: "RFC 822 with numeric offset", layout: time.RFC822Z},
+	{key: "rfc2822", name: "RFC 2822 (email date)", layout: "Mon, 2 Jan 2006 15:04:05 -0700"},
+	{key: "ansic", name: "ANSI C asctime", layout: time.ANSIC},
+	{key: "unixdate", name: "Unix date", layout: time.UnixDate},
+	{key: "rubydate", name: "Ruby date", layout: time.RubyDate},
+	{key: "clf", name: "Apache Common Log Format", layout: "02/Jan/2006:15:04:05 -0700"},
+	{key: "syslog", name: "syslog (RFC 3164)", layout: time.Stamp, noYear: true},
+	{key: "month-day-year", name: "month day, year", layout: "Jan 2, 2006"},
+	{key: "month-day-year-time", name: "month day, year and time", layout: "Jan 2, 2006 15:04:05"},
+	{key: "full-month-day-year", name: "full month day, year", layout: "January 2, 2006"},
+	{key: "full-month-day-year-time", name: "full month day, year and time", layout: "January 2, 2006 15:04:05"},
+	{key: "day-month-year", name: "day month year", layout: "2 Jan 2006"},
+	{key: "day-month-year-time", name: "day month year and time", layout: "2 Jan 2006 15:04:05"},
+	{key: "day-full-month-year", name: "day full month year", layout: "2 January 2006"},
+}
+
+// lookupTimeLayout returns the catalog entry for key, or a custom entry
+// when format is a Go reference layout instead.
+func lookupTimeLayout(format string) timeLayout {
+	for _, l := range timeLayouts {
+		if strings.EqualFold(l.key, format) {
+			return l
+		}
+	}
+	return timeLayout{name: "custom", layout: format}
+}
+
+// parseTimeString parses input with the given --input-format layouts, or
+// with every catalog layout when there are none. It returns the time and
+// a description of the layout and zone that applied. Offsets and zones in
+// the input are honoured, other times are read in loc, and yearless ones
+// get the year of now.
+func parseTimeString(input string, formats []string, now time.Time, loc *time.Location) (time.Time, string, error) {
+	input = strings.TrimSpace(input)
+	// Log lines wrap CLF timestamps in brackets.
+	if strings.HasPrefix(input, "[") && strings.HasSuffix(input, "]") {
+		input = strings.TrimSpace(input[1 : len(input)-1])
+	}
+
+	layouts := timeLayouts
+	if len(formats) > 0 {
+		layouts = nil
+		for _, format := range formats {
+			layouts = append(layouts, lookupTimeLayout(format))
+		}
+	}
+
+	for _, l := range layouts {
+		t, err := time.ParseInLocation(l.layout, input, loc)
+		if err != nil {
+			continue
+		}
+		if t, err = applyZoneAbbreviation(t, l.layout); err != nil {
+			return time.Time{}, "", fmt.Errorf("%q: %w", input, err)
+		}
+		if l.noYear {
+			t = withCurrentYear(t, now)
+		}
+		zone := "offset in input"
+		if !layoutHasZone(l.layout) {
+			zone = "read in " + loc.String()
+		}
+		return t, fmt.Sprintf("%s, layout %q, %s", l.name, l.layout, zone), nil
+	}
+
+	if len(formats) > 0 {
+		return time.Time{}, "", fmt.Errorf("%q does not match --input-format %s", input, strings.Join(formats, " or "))
+	}
+	return time.Time{}, "", fmt.Errorf("%q does not match any known format; use --input-format with a Go reference layout", input)
+}
+
+// zoneAbbreviations maps common US and European zone abbreviations to
+// their offsets from UTC in seconds. Ambiguous ones such as IST are left
+// out.
+var zoneAbbreviations = map[string]int{
+	"UTC": 0, "UT": 0, "GMT": 0,
+	"EST": -5 * 3600, "EDT": -4 * 3600,
+	"CST": -6 * 3600, "CDT": -5 * 3600,
+	"MST": -7 * 3600, "MDT": -6 * 3600,
+	"PST": -8 * 3600, "PDT": -7 * 3600,
+	"AKST": -9 * 3600, "AKDT": -8 * 3600,
+	"HST": -10 * 3600,
+	"WET": 0, "WEST": 1 * 3600, "BST": 1 * 3600,
+	"CET": 1 * 3600, "CEST": 2 * 3600,
+	"EET": 2 * 3600, "EEST": 3 * 3600,
+	"MSK": 3 * 3600,
+}
+
+// applyZoneAbbreviation corrects times parsed with a zone abbreviation and
+// no numeric offset. time.ParseInLocation only knows the abbreviations of
+// the location it parses in and gives any other a zero offset, so known
+// abbreviations are mapped here and unknown ones rejected rather than read
+// as UTC.
+func applyZoneAbbreviation(t time.Time, layout string) (time.Time, error) {
+	if !strings.Contains(layout, "MST") || strings.Contains(layout, "-07") || strings.Contains(layout, "Z07") {
+		return t, nil
+	}
+	name, offset := t.Zone()
+	if offset != 0 {
+		// The abbreviation belongs to the location.
+		return t, nil
+	}
+	known, ok := zoneAbbreviations[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown zone abbreviation %s; use a numeric offset such as -0500", name)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone(name, known)), nil
+}
+
+// layoutHasZone reports whether a reference layout contains an offset or
+// zone abbreviation.
+func layoutHasZone(layout string) bool {
+	for _, zone := range []string{"MST", "Z07", "-07"} {
+		if strings.Contains(layout, zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// withCurrentYear places a yearless syslog time in the year of now, or in
+// the year before when that would put it more than a day in the future
+// (a December log read in January).
+func withCurrentYear(t, now time.Time) time.Time {
+	year := now.In(t.Location()).Year()
+	dated := time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if dated.After(now.Add(24 * time.Hour)) {
+		dated = dated.AddDate(-1, 0, 0)
+	}
+	return dated
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -88,6 +89,194 @@ func TestTimeCommand(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "millisecond epoch is detected",
+			args:  []string{"1700000000123"},
+			flags: map[string]string{},
+			checkFunc: func(output string) bool {
+				return strings.Contains(output, "Formatted time (UTC): 2023-11-14 22:13:20\n") &&
+					strings.Contains(output, "ISO 8601: 2023-11-14T22:13:20.123Z\n") &&
+					strings.Contains(output, "Unix milliseconds: 1700000000123\n") &&
+					strings.Contains(output, "Unix microseconds: 1700000000123000\n") &&
+					strings.Contains(output, "Unix nanoseconds: 1700000000123000000\n") &&
+					strings.HasSuffix(output, "Input format: Unix milliseconds (detected by magnitude)")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "explicit unit",
+			args:  []string{"1700000000"},
+			flags: map[string]string{"unit": "ms"},
+			checkFunc: func(output string) bool {
+				return strings.Contains(output, "Formatted time (UTC): 1970-01-20 16:13:20")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "timestamp output in nanoseconds",
+			args:  []string{"2021-01-01"},
+			flags: map[string]string{"timestamp": "true", "unit": "ns"},
+			checkFunc: func(output string) bool {
+				return output == "Unix nanoseconds: 1609459200000000000"
+			},
+			wantErr: false,
+		},
+		{
+			name:      "invalid unit",
+			args:      []string{"2021-01-01"},
+			flags:     map[string]string{"unit": "days"},
+			checkFunc: func(output string) bool { return true },
+			wantErr:   true,
+		},
+		{
+			name:  "RFC 1123 input reports its layout",
+			args:  []string{"Tue, 05 Mar 2024 10:00:00 GMT"},
+			flags: map[string]string{},
+			checkFunc: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) == 8 &&
+					strings.Contains(lines[0], "Formatted time (UTC): 2024-03-05 10:00:00") &&
+					lines[7] == `Input format: RFC 1123 (HTTP date), layout "Mon, 02 Jan 2006 15:04:05 MST", offset in input`
+			},
+			wantErr: false,
+		},
+		{
+			name:  "explicit input format",
+			args:  []string{"05.03.2024 10:30"},
+			flags: map[string]string{"input-format": "02.01.2006 15:04"},
+			checkFunc: func(output string) bool {
+				return strings.Contains(output, "Formatted time (UTC): 2024-03-05 10:30:00") &&
+					strings.Contains(output, `Input format: custom, layout "02.01.2006 15:04"`)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "input not matching the input format",
+			args:      []string{"2024-03-05"},
+			flags:     map[string]string{"input-format": "clf"},
+			checkFunc: func(output string) bool { return true },
+			wantErr:   true,
+		},
+		{
+			name:  "input in another timezone",
+			args:  []string{"2024-01-01 09:00"},
+			flags: map[string]string{"from-timezone": "America/New_York"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-01-01 14:00:00\n") &&
+					strings.HasSuffix(output, "read in America/New_York")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "offset in input wins over the input timezone",
+			args:  []string{"2024-01-01T09:00:00+01:00"},
+			flags: map[string]string{"from-timezone": "America/New_York"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-01-01 08:00:00\n") &&
+					strings.HasSuffix(output, "offset in input")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "several output timezones",
+			args:  []string{"1704117600"},
+			flags: map[string]string{"timezone": "UTC,Asia/Tokyo,America/New_York"},
+			checkFunc: func(output string) bool {
+				lines := strings.Split(output, "\n")
+				return len(lines) >= 4 &&
+					lines[0] == "Formatted time (UTC): 2024-01-01 14:00:00" &&
+					lines[1] == "Formatted time (Asia/Tokyo): 2024-01-01 23:00:00" &&
+					lines[2] == "Formatted time (America/New_York): 2024-01-01 09:00:00" &&
+					lines[3] == "Unix timestamp: 1704117600"
+			},
+			wantErr: false,
+		},
+		{
+			name:      "invalid input timezone",
+			args:      []string{"2021-01-01"},
+			flags:     map[string]string{"from-timezone": "Invalid/Zone"},
+			checkFunc: func(output string) bool { return true },
+			wantErr:   true,
+		},
+		{
+			name:  "relative expression in the output timezone",
+			args:  []string{"tomorrow noon"},
+			flags: map[string]string{"now": "2024-03-06T23:30:00Z", "timezone": "Europe/Paris"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (Europe/Paris): 2024-03-08 12:00:00\n") &&
+					strings.HasSuffix(output, "Input format: natural language, relative to 2024-03-07T00:30:00+01:00")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "relative expression in the input timezone",
+			args:  []string{"next friday 17:00"},
+			flags: map[string]string{"now": "1709720100", "from-timezone": "America/New_York"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-03-08 22:00:00\n")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "current time from --now",
+			args:  []string{},
+			flags: map[string]string{"now": "2024-03-06 10:15:00"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-03-06 10:15:00\n")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "--now ignores --input-format",
+			args:  []string{"Mar  5 10:00:00"},
+			flags: map[string]string{"now": "2024-03-06T10:00:00Z", "input-format": "syslog"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-03-05 10:00:00\n")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "--now ignores --unit",
+			args:  []string{"tomorrow"},
+			flags: map[string]string{"now": "1709720100", "unit": "ms"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-03-07 00:00:00\n")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "zone abbreviation other than --from-timezone",
+			args:  []string{"Tue, 05 Mar 2024 10:00:00 PST"},
+			flags: map[string]string{"from-timezone": "America/New_York"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-03-05 18:00:00\n") &&
+					strings.HasSuffix(output, "offset in input")
+			},
+			wantErr: false,
+		},
+		{
+			name:  "zone abbreviation of --from-timezone",
+			args:  []string{"Tue, 05 Mar 2024 10:00:00 EST"},
+			flags: map[string]string{"from-timezone": "America/New_York"},
+			checkFunc: func(output string) bool {
+				return strings.HasPrefix(output, "Formatted time (UTC): 2024-03-05 15:00:00\n")
+			},
+			wantErr: false,
+		},
+		{
+			name:      "unknown zone abbreviation",
+			args:      []string{"Tue, 05 Mar 2024 10:00:00 XYZ"},
+			flags:     map[string]string{},
+			checkFunc: func(output string) bool { return true },
+			wantErr:   true,
+		},
+		{
+			name:      "invalid --now",
+			args:      []string{"tomorrow"},
+			flags:     map[string]string{"now": "soon"},
+			checkFunc: func(output string) bool { return true },
+			wantErr:   true,
+		},
 		{
 			name:      "invalid date format",
 			args:      []string{"invalid-date"},
@@ -109,7 +298,11 @@ func TestTimeCommand(t *testing.T) {
 			// Reset flags to default values
 			timeFormat = "2006-01-02 15:04:05"
 			toTimestamp = false
-			timeTimezone = "UTC"
+			timeTimezone = []string{"UTC"}
+			timeFrom = "UTC"
+			timeInput = nil
+			timeUnit = "auto"
+			timeNow = ""
 
 			// Create a new command instance for testing
 			cmd := &cobra.Command{
@@ -121,7 +314,11 @@ func TestTimeCommand(t *testing.T) {
 			// Add flags
 			cmd.Flags().StringVarP(&timeFormat, "format", "f", "2006-01-02 15:04:05", "Output format for time")
 			cmd.Flags().BoolVarP(&toTimestamp, "timestamp", "t", false, "Convert to Unix timestamp instead")
-			cmd.Flags().StringVarP(&timeTimezone, "timezone", "z", "UTC", "Timezone (UTC, Local, or IANA name)")
+			cmd.Flags().StringSliceVarP(&timeTimezone, "timezone", "z", []string{"UTC"}, "Output timezones (UTC, Local, or IANA name)")
+			cmd.Flags().StringVar(&timeFrom, "from-timezone", "UTC", "Timezone of input without an offset")
+			cmd.Flags().StringArrayVarP(&timeInput, "input-format", "i", nil, "Input layout: a catalog key or Go reference layout")
+			cmd.Flags().StringVarP(&timeUnit, "unit", "u", "auto", "Epoch precision for numeric input")
+			cmd.Flags().StringVar(&timeNow, "now", "", "Reference time for relative expressions")
 
 			// Set flags
 			for flag, value := range tt.flags {
@@ -160,6 +357,216 @@ func TestTimeCommand(t *testing.T) {
 	}
 }
 
+func TestParseTimeString(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		formats []string
+		want    string
+		layout  string
+		wantErr bool
+	}{
+		{input: "2024-03-05T10:00:00.123Z", want: "2024-03-05T10:00:00.123Z", layout: "RFC 3339"},
+		{input: "2024-03-05T10:00:00-05:00", want: "2024-03-05T15:00:00Z", layout: "RFC 3339"},
+		{input: "2024-03-05T10:00:00+0530", want: "2024-03-05T04:30:00Z", layout: "ISO 8601 (basic offset)"},
+		{input: "2024-03-05T10:00:00", want: "2024-03-05T10:00:00Z", layout: "ISO 8601 (no offset)"},
+		{input: "2024-03-05T10:00", want: "2024-03-05T10:00:00Z", layout: "ISO 8601 (minutes, no offset)"},
+		{input: "2024-03-05T10:00+01:00", want: "2024-03-05T09:00:00Z", layout: "ISO 8601 (minutes)"},
+		{input: "20240305T100000Z", want: "2024-03-05T10:00:00Z", layout: "ISO 8601 (basic)"},
+		{input: "2024-03-05 10:00:00.5 +0000 UTC", want: "2024-03-05T10:00:00.5Z", layout: "Go time.String"},
+		{input: "2024-03-05 10:00", want: "2024-03-05T10:00:00Z", layout: "date and time (minutes)"},
+		{input: "Tue, 05 Mar 2024 10:00:00 GMT", want: "2024-03-05T10:00:00Z", layout: "RFC 1123 (HTTP date)"},
+		{input: "Tue, 05 Mar 2024 10:00:00 EST", want: "2024-03-05T15:00:00Z", layout: "RFC 1123 (HTTP date)"},
+		{input: "Tue, 05 Mar 2024 10:00:00 CEST", want: "2024-03-05T08:00:00Z", layout: "RFC 1123 (HTTP date)"},
+		{input: "2024-03-05 10:00:00 PDT", want: "2024-03-05T17:00:00Z", layout: "date and time with zone"},
+		{input: "Tue, 05 Mar 2024 10:00:00 XYZ", wantErr: true},
+		{input: "2024-03-05 10:00:00 +0000 XYZ", want: "2024-03-05T10:00:00Z", layout: "Go time.String"},
+		{input: "Tue, 5 Mar 2024 10:00:00 +0100", want: "2024-03-05T09:00:00Z", layout: "RFC 2822 (email date)"},
+		{input: "Tuesday, 05-Mar-24 10:00:00 UTC", want: "2024-03-05T10:00:00Z", layout: "RFC 850"},
+		{input: "Tue Mar  5 10:00:00 2024", want: "2024-03-05T10:00:00Z", layout: "ANSI C asctime"},
+		{input: "[05/Mar/2024:10:00:00 -0800]", want: "2024-03-05T18:00:00Z", layout: "Apache Common Log Format"},
+		{input: "Jan  9 23:59:59", want: "2024-01-09T23:59:59Z", layout: "syslog (RFC 3164)"},
+		{input: "Dec 31 23:59:59", want: "2023-12-31T23:59:59Z", layout: "syslog (RFC 3164)"},
+		{input: "March 5, 2024", want: "2024-03-05T00:00:00Z", layout: "full month day, year"},
+		{input: "5 Mar 2024", want: "2024-03-05T00:00:00Z", layout: "day month year"},
+		{input: "05.03.2024", formats: []string{"02.01.2006"}, want: "2024-03-05T00:00:00Z", layout: "custom"},
+		{input: "05/Mar/2024:10:00:00 +0000", formats: []string{"rfc3339", "CLF"}, want: "2024-03-05T10:00:00Z", layout: "Apache Common Log Format"},
+		{input: "2024-03-05", formats: []string{"rfc3339"}, wantErr: true},
+		{input: "yesterday-ish", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, layout, err := parseTimeString(tt.input, tt.formats, now, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if s := got.UTC().Format(time.RFC3339Nano); s != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, s)
+			}
+			if !strings.HasPrefix(layout, tt.layout+", layout ") {
+				t.Errorf("Expected layout %s, got %s", tt.layout, layout)
+			}
+		})
+	}
+}
+
+func TestParseEpoch(t *testing.T) {
+	tests := []struct {
+		input   string
+		unit    string
+		want    string
+		detail  string
+		isEpoch bool
+		wantErr bool
+	}{
+		{input: "1700000000", unit: "auto", want: "2023-11-14T22:13:20Z", detail: "Unix seconds (detected by magnitude)", isEpoch: true},
+		{input: "1700000000000", unit: "auto", want: "2023-11-14T22:13:20Z", detail: "Unix milliseconds (detected by magnitude)", isEpoch: true},
+		{input: "1700000000000001", unit: "auto", want: "2023-11-14T22:13:20.000001Z", detail: "Unix microseconds (detected by magnitude)", isEpoch: true},
+		{input: "1700000000000000001", unit: "auto", want: "2023-11-14T22:13:20.000000001Z", detail: "Unix nanoseconds (detected by magnitude)", isEpoch: true},
+		{input: "-1500", unit: "auto", want: "1969-12-31T23:35:00Z", detail: "Unix seconds (detected by magnitude)", isEpoch: true},
+		{input: "-1.5", unit: "auto", want: "1969-12-31T23:59:58.5Z", detail: "Unix seconds (detected by magnitude)", isEpoch: true},
+		{input: "1.7e12", unit: "auto", want: "2023-11-14T22:13:20Z", detail: "Unix milliseconds (detected by magnitude)", isEpoch: true},
+		{input: "1_700_000_000", unit: "auto", want: "2023-11-14T22:13:20Z", detail: "Unix seconds (detected by magnitude)", isEpoch: true},
+		{input: "1700000000", unit: "US", want: "1970-01-01T00:28:20Z", detail: "Unix microseconds (from --unit)", isEpoch: true},
+		{input: "1700000000000", unit: "s", isEpoch: true, wantErr: true},
+		{input: "2024-03-05", unit: "auto", isEpoch: false},
+		{input: "1/2", unit: "auto", isEpoch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+" "+tt.unit, func(t *testing.T) {
+			got, detail, isEpoch, err := parseEpoch(tt.input, tt.unit)
+			if isEpoch != tt.isEpoch {
+				t.Fatalf("Expected epoch %v, got %v", tt.isEpoch, isEpoch)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !isEpoch {
+				return
+			}
+			if s := got.UTC().Format(time.RFC3339Nano); s != tt.want || detail != tt.detail {
+				t.Errorf("Expected %s (%s), got %s (%s)", tt.want, tt.detail, s, detail)
+			}
+		})
+	}
+}
+
+func TestParseNaturalTime(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, 3, 6, 10, 15, 30, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"now", "2024-03-06 10:15:30"},
+		{"today", "2024-03-06 00:00:00"},
+		{"Tomorrow noon", "2024-03-07 12:00:00"},
+		{"yesterday at 5 pm", "2024-03-05 17:00:00"},
+		{"tomorrow 9:30am", "2024-03-07 09:30:00"},
+		{"midnight", "2024-03-06 00:00:00"},
+		{"3 days ago", "2024-03-03 10:15:30"},
+		{"in 2 hours", "2024-03-06 12:15:30"},
+		{"90 minutes", "2024-03-06 11:45:30"},
+		{"a week from now", "2024-03-13 10:15:30"},
+		{"an hour ago", "2024-03-06 09:15:30"},
+		{"-2 days", "2024-03-04 10:15:30"},
+		{"2 years ago", "2022-03-06 10:15:30"},
+		{"wednesday", "2024-03-06 00:00:00"},
+		{"friday", "2024-03-08 00:00:00"},
+		{"this fri", "2024-03-08 00:00:00"},
+		{"monday", "2024-03-11 00:00:00"},
+		{"next wednesday", "2024-03-13 00:00:00"},
+		{"next friday 17:00", "2024-03-08 17:00:00"},
+		{"last wednesday", "2024-02-28 00:00:00"},
+		{"last monday at 9:00:15", "2024-03-04 09:00:15"},
+		{"next week", "2024-03-13 10:15:30"},
+		{"last month", "2024-02-06 10:15:30"},
+		{"start of day", "2024-03-06 00:00:00"},
+		{"start of week", "2024-03-04 00:00:00"},
+		{"start of the month", "2024-03-01 00:00:00"},
+		{"beginning of next month", "2024-04-01 00:00:00"},
+		{"start of year", "2024-01-01 00:00:00"},
+		{"end of last month", "2024-02-29 23:59:59"},
+		{"end of week", "2024-03-10 23:59:59"},
+		{"start of next week 9am", "2024-03-11 09:00:00"},
+		{"next friday at 25:00", ""},
+		{"13pm", ""},
+		{"start of hour", ""},
+		{"3 bananas ago", ""},
+		{"2024-03-05", ""},
+		{"in", ""},
+		{"at", ""},
+		{"later", ""},
+		{"from now", ""},
+		{"in at", ""},
+		{"now later", "2024-03-06 10:15:30"},
+		{"2 hours later", "2024-03-06 12:15:30"},
+		{"9999999999999 hours", "out of range"},
+		{"9999999 days ago", "out of range"},
+		{"in 8000 years", "out of range"},
+		{"in 100000 hours", "2035-08-03 02:15:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok, err := parseNaturalTime(tt.input, now)
+			if tt.want == "out of range" {
+				if !ok || err == nil {
+					t.Errorf("Expected an out of range error, got %v (%v)", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Expected no match, got %v", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("Expected a match")
+			}
+			if s := got.Format("2006-01-02 15:04:05"); s != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, s)
+			}
+		})
+	}
+
+	// Months keep the day where it exists, and days keep the wall clock
+	// across a DST change.
+	jan31 := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	if got, _, _ := parseNaturalTime("next month", jan31); got.Format("2006-01-02") != "2024-02-29" {
+		t.Errorf("Expected Jan 31 plus a month to be 2024-02-29, got %v", got)
+	}
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("Europe/Paris timezone not available")
+	}
+	beforeDST := time.Date(2024, 3, 30, 12, 0, 0, 0, paris)
+	if got, _, _ := parseNaturalTime("tomorrow noon", beforeDST); got.Format("2006-01-02 15:04 -0700") != "2024-03-31 12:00 +0200" {
+		t.Errorf("Expected noon CEST after the DST change, got %v", got)
+	}
+	if got, _, _ := parseNaturalTime("in 1 day", beforeDST); got.Format("15:04") != "12:00" {
+		t.Errorf("Expected a day later to keep the wall clock, got %v", got)
+	}
+}
+
 func TestParseTimezone(t *testing.T) {
 	tests := []struct {
 		name     string
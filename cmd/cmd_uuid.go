@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var uuidCmd = &cobra.Command{
+	Use:   "uuid",
+	Short: "Work with UUIDs",
+	Long:  `Inspect UUIDs. Use "plz random --type uuid" to generate them.`,
+}
+
+var uuidInspectCmd = &cobra.Command{
+	Use:   "inspect [uuid]",
+	Short: "Show the version, variant, and embedded fields of a UUID",
+	Long: `Parse a UUID and report its version and variant. Time-based UUIDs (v1, v6,
+v7) also report their embedded timestamp, and v1/v6 their clock sequence
+and node.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUUIDInspect,
+}
+
+var (
+	uuidTimeFormat string
+	uuidTimezone   string
+)
+
+// gregorianOffset is the number of 100ns intervals between the Gregorian
+// epoch (1582-10-15) used by v1/v6 UUIDs and the Unix epoch.
+const gregorianOffset = 0x01B21DD213814000
+
+// uuidNamespaces are the predefined namespaces from RFC 9562 appendix C.
+var uuidNamespaces = map[string]string{
+	"dns":  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	"url":  "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+	"oid":  "6ba7b812-9dad-11d1-80b4-00c04fd430c8",
+	"x500": "6ba7b814-9dad-11d1-80b4-00c04fd430c8",
+}
+
+var uuidVersionNames = map[int]string{
+	1: "Gregorian time-based",
+	2: "DCE security",
+	3: "name-based (MD5)",
+	4: "random",
+	5: "name-based (SHA-1)",
+	6: "reordered Gregorian time-based",
+	7: "Unix Epoch time-based",
+	8: "custom",
+}
+
+func init() {
+	uuidInspectCmd.Flags().StringVarP(&uuidTimeFormat, "format", "f", "2006-01-02 15:04:05.000", "Output format for embedded timestamps")
+	uuidInspectCmd.Flags().StringVarP(&uuidTimezone, "timezone", "z", "UTC", "Timezone for embedded timestamps (UTC, Local, or IANA name)")
+	uuidCmd.AddCommand(uuidInspectCmd)
+	rootCmd.AddCommand(uuidCmd)
+}
+
+func runUUIDInspect(cmd *cobra.Command, args []string) error {
+	location, err := parseTimezone(uuidTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	b, err := parseUUID(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("UUID: %s\n", formatUUID(b))
+
+	switch {
+	case isAllBytes(b, 0x00):
+		fmt.Println("Version: nil UUID")
+		return nil
+	case isAllBytes(b, 0xff):
+		fmt.Println("Version: max UUID")
+		return nil
+	}
+
+	variant := uuidVariant(b)
+	fmt.Printf("Variant: %s\n", variant)
+	if variant != "RFC 9562" {
+		return nil
+	}
+
+	version := int(b[6] >> 4)
+	name, ok := uuidVersionNames[version]
+	if !ok {
+		name = "unknown"
+	}
+	fmt.Printf("Version: %d (%s)\n", version, name)
+
+	switch version {
+	case 1, 6:
+		ts := uuidGregorianTime(b, version)
+		fmt.Printf("Timestamp: %s (%s)\n", formatTime(ts, location, uuidTimeFormat), uuidTimezone)
+		fmt.Printf("Clock sequence: %d\n", binary.BigEndian.Uint16(b[8:10])&0x3fff)
+		node := net.HardwareAddr(b[10:16])
+		kind := "MAC address"
+		if b[10]&0x01 != 0 {
+			kind = "random"
+		}
+		fmt.Printf("Node: %s (%s)\n", node, kind)
+	case 7:
+		ms := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, b[0:6]...)))
+		ts := time.UnixMilli(ms)
+		fmt.Printf("Timestamp: %s (%s)\n", formatTime(ts, location, uuidTimeFormat), uuidTimezone)
+		fmt.Printf("Unix milliseconds: %d\n", ms)
+	}
+	return nil
+}
+
+// generateUUIDVersion creates a UUID of the given RFC 9562 version. Name-based
+// versions (3 and 5) hash name within namespace, which may be one of the
+// predefined names (dns, url, oid, x500) or any UUID.
+func generateUUIDVersion(version int, namespace, name string) (string, error) {
+	var b []byte
+	var err error
+	switch version {
+	case 1, 6:
+		b, err = newGregorianUUID(version, time.Now())
+	case 3, 5:
+		b, err = newNameUUID(version, namespace, name)
+	case 4:
+		return generateUUID()
+	case 7:
+		b, err = newUnixTimeUUID(time.Now())
+	default:
+		return "", fmt.Errorf("unsupported UUID version: %d (supported: 1, 3, 4, 5, 6, 7)", version)
+	}
+	if err != nil {
+		return "", err
+	}
+	return formatUUID(b), nil
+}
+
+func newGregorianUUID(version int, now time.Time) ([]byte, error) {
+	b := make([]byte, 16)
+	// Random clock sequence and node; the multicast bit marks the node as
+	// not being a real MAC address.
+	if err := randomBytes(b[8:]); err != nil {
+		return nil, err
+	}
+	b[10] |= 0x01
+
+	ts := uint64(now.UnixNano()/100) + gregorianOffset
+	if version == 1 {
+		binary.BigEndian.PutUint32(b[0:4], uint32(ts))
+		binary.BigEndian.PutUint16(b[4:6], uint16(ts>>32))
+		binary.BigEndian.PutUint16(b[6:8], uint16(ts>>48)&0x0fff|0x1000)
+	} else {
+		binary.BigEndian.PutUint32(b[0:4], uint32(ts>>28))
+		binary.BigEndian.PutUint16(b[4:6], uint16(ts>>12))
+		binary.BigEndian.PutUint16(b[6:8], uint16(ts)&0x0fff|0x6000)
+	}
+	b[8] = (b[8] & 0x3f) | 0x80
+	return b, nil
+}
+
+func newNameUUID(version int, namespace, name string) ([]byte, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("UUID v%d requires --namespace", version)
+	}
+	if known, ok := uuidNamespaces[strings.ToLower(namespace)]; ok {
+		namespace = known
+	}
+	ns, err := parseUUID(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+
+	data := append(ns, name...)
+	var b []byte
+	if version == 3 {
+		sum := md5.Sum(data)
+		b = sum[:]
+	} else {
+		sum := sha1.Sum(data)
+		b = sum[:16]
+	}
+	b[6] = (b[6] & 0x0f) | byte(version<<4)
+	b[8] = (b[8] & 0x3f) | 0x80
+	return b, nil
+}
+
+func newUnixTimeUUID(now time.Time) ([]byte, error) {
+	b := make([]byte, 16)
+	if err := randomBytes(b[6:]); err != nil {
+		return nil, err
+	}
+	ms := uint64(now.UnixMilli())
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	b[6] = (b[6] & 0x0f) | 0x70
+	b[8] = (b[8] & 0x3f) | 0x80
+	return b, nil
+}
+
+// uuidGregorianTime extracts the 60-bit timestamp of a v1 or v6 UUID.
+func uuidGregorianTime(b []byte, version int) time.Time {
+	var ts uint64
+	if version == 1 {
+		ts = uint64(binary.BigEndian.Uint16(b[6:8])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(b[4:6]))<<32 |
+			uint64(binary.BigEndian.Uint32(b[0:4]))
+	} else {
+		ts = uint64(binary.BigEndian.Uint32(b[0:4]))<<28 |
+			uint64(binary.BigEndian.Uint16(b[4:6]))<<12 |
+			uint64(binary.BigEndian.Uint16(b[6:8])&0x0fff)
+	}
+	unix100ns := int64(ts - gregorianOffset)
+	return time.Unix(unix100ns/1e7, unix100ns%1e7*100)
+}
+
+func uuidVariant(b []byte) string {
+	switch {
+	case b[8]&0x80 == 0:
+		return "NCS (reserved)"
+	case b[8]&0xc0 == 0x80:
+		return "RFC 9562"
+	case b[8]&0xe0 == 0xc0:
+		return "Microsoft (reserved)"
+	default:
+		return "future (reserved)"
+	}
+}
+
+// parseUUID accepts the canonical form as well as braced, "urn:uuid:"
+// prefixed, and undashed hex forms.
+func parseUUID(s string) ([]byte, error) {
+	text := strings.TrimSpace(strings.ToLower(s))
+	text = strings.TrimPrefix(text, "urn:uuid:")
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "{"), "}")
+	if len(text) == 36 {
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return nil, fmt.Errorf("invalid UUID: %s", s)
+		}
+		text = strings.ReplaceAll(text, "-", "")
+	}
+	if len(text) != 32 {
+		return nil, fmt.Errorf("invalid UUID: %s", s)
+	}
+	b, err := hex.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID: %s", s)
+	}
+	return b, nil
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func isAllBytes(b []byte, v byte) bool {
+	for _, c := range b {
+		if c != v {
+			return false
+		}
+	}
+	return true
+}
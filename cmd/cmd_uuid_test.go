@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestUUIDInspectCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		flags    map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "rfc 9562 v1 example",
+			args: []string{"C232AB00-9414-11EC-B3C8-9F6BDECED846"},
+			expected: "UUID: c232ab00-9414-11ec-b3c8-9f6bdeced846\n" +
+				"Variant: RFC 9562\n" +
+				"Version: 1 (Gregorian time-based)\n" +
+				"Timestamp: 2022-02-22 19:22:22.000 (UTC)\n" +
+				"Clock sequence: 13256\n" +
+				"Node: 9f:6b:de:ce:d8:46 (random)",
+		},
+		{
+			name: "rfc 9562 v6 example",
+			args: []string{"1EC9414C-232A-6B00-B3C8-9F6BDECED846"},
+			expected: "UUID: 1ec9414c-232a-6b00-b3c8-9f6bdeced846\n" +
+				"Variant: RFC 9562\n" +
+				"Version: 6 (reordered Gregorian time-based)\n" +
+				"Timestamp: 2022-02-22 19:22:22.000 (UTC)\n" +
+				"Clock sequence: 13256\n" +
+				"Node: 9f:6b:de:ce:d8:46 (random)",
+		},
+		{
+			name:  "rfc 9562 v7 example in another timezone",
+			args:  []string{"urn:uuid:017f22e2-79b0-7cc3-98c4-dc0c0c07398f"},
+			flags: map[string]string{"timezone": "Asia/Tokyo", "format": "2006-01-02 15:04"},
+			expected: "UUID: 017f22e2-79b0-7cc3-98c4-dc0c0c07398f\n" +
+				"Variant: RFC 9562\n" +
+				"Version: 7 (Unix Epoch time-based)\n" +
+				"Timestamp: 2022-02-23 04:22 (Asia/Tokyo)\n" +
+				"Unix milliseconds: 1645557742000",
+		},
+		{
+			name: "reserved variant without dashes",
+			args: []string{"919108f752d133205bacf847db4148a8"},
+			expected: "UUID: 919108f7-52d1-3320-5bac-f847db4148a8\n" +
+				"Variant: NCS (reserved)",
+		},
+		{
+			name:     "nil uuid",
+			args:     []string{"{00000000-0000-0000-0000-000000000000}"},
+			expected: "UUID: 00000000-0000-0000-0000-000000000000\nVersion: nil UUID",
+		},
+		{
+			name:    "invalid uuid",
+			args:    []string{"not-a-uuid"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reset flags to default values
+			uuidTimeFormat = "2006-01-02 15:04:05.000"
+			uuidTimezone = "UTC"
+
+			cmd := &cobra.Command{
+				Use:  "inspect [uuid]",
+				Args: cobra.ExactArgs(1),
+				RunE: runUUIDInspect,
+			}
+			cmd.Flags().StringVarP(&uuidTimeFormat, "format", "f", "2006-01-02 15:04:05.000", "Output format for embedded timestamps")
+			cmd.Flags().StringVarP(&uuidTimezone, "timezone", "z", "UTC", "Timezone for embedded timestamps")
+
+			for flag, value := range tt.flags {
+				cmd.Flags().Set(flag, value)
+			}
+
+			old := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := cmd.RunE(cmd, tt.args)
+
+			w.Close()
+			os.Stdout = old
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := strings.TrimSpace(buf.String())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if output != tt.expected {
+					t.Errorf("Expected output %q, got %q", tt.expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateUUIDVersion(t *testing.T) {
+	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	for _, version := range []int{1, 4, 6, 7} {
+		result, err := generateUUIDVersion(version, "", "")
+		if err != nil {
+			t.Fatalf("v%d: unexpected error: %v", version, err)
+		}
+		match := uuidRegex.FindStringSubmatch(result)
+		if match == nil || match[1] != string(rune('0'+version)) {
+			t.Errorf("v%d: unexpected UUID %s", version, result)
+		}
+	}
+
+	t.Run("name-based", func(t *testing.T) {
+		tests := []struct {
+			version   int
+			namespace string
+			expected  string
+		}{
+			{3, "dns", "6fa459ea-ee8a-3ca4-894e-db77e160355e"},
+			{5, "dns", "886313e1-3b8a-5372-9b90-0c9aee199e5d"},
+			{5, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "886313e1-3b8a-5372-9b90-0c9aee199e5d"},
+		}
+		for _, tt := range tests {
+			result, err := generateUUIDVersion(tt.version, tt.namespace, "python.org")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("v%d: expected %s, got %s", tt.version, tt.expected, result)
+			}
+		}
+	})
+
+	t.Run("v7 is time ordered", func(t *testing.T) {
+		first, _ := generateUUIDVersion(7, "", "")
+		second, _ := generateUUIDVersion(7, "", "")
+		if first[:8] > second[:8] {
+			t.Errorf("Expected %s to sort before %s", first, second)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := generateUUIDVersion(5, "", "name"); err == nil {
+			t.Error("Expected error when namespace is missing")
+		}
+		if _, err := generateUUIDVersion(2, "", ""); err == nil {
+			t.Error("Expected error for unsupported version")
+		}
+	})
+}
@@ -31,7 +31,7 @@ func TestRootCommand(t *testing.T) {
 }
 
 func TestRootCommandHasSubcommands(t *testing.T) {
-	expectedCommands := []string{"encode", "hash", "json", "random", "time"}
+	expectedCommands := []string{"cert", "encode", "fake", "hash", "json", "random", "time", "uuid"}
 
 	for _, expectedCmd := range expectedCommands {
 		found := false
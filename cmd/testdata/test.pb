@@ -0,0 +1 @@
+testing
\ No newline at end of file